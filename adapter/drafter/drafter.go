@@ -17,6 +17,15 @@ import (
 )
 
 func Parse(r io.Reader) ([]byte, error) {
+	return parse(r, false)
+}
+
+// ParseWithSourceMap parses API blueprint including source maps of every element
+func ParseWithSourceMap(r io.Reader) ([]byte, error) {
+	return parse(r, true)
+}
+
+func parse(r io.Reader, sourcemap bool) ([]byte, error) {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
@@ -33,7 +42,7 @@ func Parse(r io.Reader) ([]byte, error) {
 
 	C.free(unsafe.Pointer(cSource))
 
-	return serialize(cResult), nil
+	return serialize(cResult, sourcemap), nil
 }
 
 func Validate(r io.Reader) ([]byte, error) {
@@ -53,15 +62,15 @@ func Validate(r io.Reader) ([]byte, error) {
 
 	C.free(unsafe.Pointer(cSource))
 
-	return serialize(cResult), nil
+	return serialize(cResult, false), nil
 }
 
 func Version() string {
 	return C.GoString(C.drafter_version_string())
 }
 
-func serialize(r *C.drafter_result) []byte {
-	options := C.drafter_serialize_options{sourcemap: false, format: C.DRAFTER_SERIALIZE_JSON}
+func serialize(r *C.drafter_result, sourcemap bool) []byte {
+	options := C.drafter_serialize_options{sourcemap: C.bool(sourcemap), format: C.DRAFTER_SERIALIZE_JSON}
 	cResult := C.drafter_serialize(r, options)
 	results := C.GoString(cResult)
 
@@ -69,3 +78,21 @@ func serialize(r *C.drafter_result) []byte {
 
 	return []byte(results)
 }
+
+// Engine exposes drafter as parser engine
+type Engine struct{}
+
+// Parse parses API blueprint as API Element JSON
+func (Engine) Parse(r io.Reader) ([]byte, error) {
+	return Parse(r)
+}
+
+// ParseWithSourceMap parses API blueprint as API Element JSON including source maps
+func (Engine) ParseWithSourceMap(r io.Reader) ([]byte, error) {
+	return ParseWithSourceMap(r)
+}
+
+// Validate validates API blueprint, the result contains annotations only
+func (Engine) Validate(r io.Reader) ([]byte, error) {
+	return Validate(r)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Field is a property of JSON body, annotated from its message body schema.
+// Path is dot separated, items of array are suffixed with `[]`, e.g. `users[].name`.
+type Field struct {
+	Path        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+type jsonSchema struct {
+	Type        interface{}            `json:"type"`
+	Description string                 `json:"description"`
+	Properties  map[string]*jsonSchema `json:"properties"`
+	Required    []string               `json:"required"`
+	Items       *jsonSchema            `json:"items"`
+}
+
+// annotate fills Pretty and Fields of JSON asset, Pretty of XML asset, other assets are kept as is.
+func (a *Asset) annotate(schema Asset) {
+	a.Pretty = a.Body
+
+	if strings.Contains(a.ContentType, "xml") {
+		if x, err := IndentXML(a.Body); err == nil {
+			a.Pretty = x
+		}
+
+		return
+	}
+
+	if !strings.Contains(a.ContentType, "json") {
+		return
+	}
+
+	var bf bytes.Buffer
+
+	if err := json.Indent(&bf, []byte(strings.TrimSpace(a.Body)), "", "  "); err == nil {
+		a.Pretty = bf.String()
+	}
+
+	s := &jsonSchema{}
+
+	if err := json.Unmarshal([]byte(schema.Body), s); err == nil {
+		a.Fields = schemaFields("", s)
+	}
+}
+
+func schemaFields(prefix string, s *jsonSchema) []Field {
+	fs := []Field{}
+
+	if s.Items != nil {
+		return append(fs, schemaFields(prefix+"[]", s.Items)...)
+	}
+
+	ks := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		ks = append(ks, k)
+	}
+
+	sort.Strings(ks)
+
+	for _, k := range ks {
+		p := s.Properties[k]
+		name := k
+
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+
+		fs = append(fs, Field{
+			Path:        name,
+			Type:        schemaType(p.Type),
+			Description: p.Description,
+			Required:    contains(s.Required, k),
+		})
+
+		fs = append(fs, schemaFields(name, p)...)
+	}
+
+	return fs
+}
+
+func schemaType(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []interface{}:
+		ss := make([]string, 0, len(t))
+		for _, x := range t {
+			if s, ok := x.(string); ok {
+				ss = append(ss, s)
+			}
+		}
+
+		return strings.Join(ss, "|")
+	}
+
+	return ""
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
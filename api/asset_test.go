@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsset_annotate(t *testing.T) {
+	a := Asset{ContentType: "application/json", Body: `{"id":1,"tags":[{"name":"a"}]}` + "\n"}
+	s := Asset{Body: `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "number", "description": "Unique identifier"},
+			"tags": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": ["string", "null"]}}}}
+		},
+		"required": ["id"]
+	}`}
+
+	a.annotate(s)
+
+	assert.Equal(t, "{\n  \"id\": 1,\n  \"tags\": [\n    {\n      \"name\": \"a\"\n    }\n  ]\n}", a.Pretty)
+	assert.Equal(t, []Field{
+		{Path: "id", Type: "number", Description: "Unique identifier", Required: true},
+		{Path: "tags", Type: "array"},
+		{Path: "tags[].name", Type: "string|null"},
+	}, a.Fields)
+}
+
+func TestAsset_annotate_plain(t *testing.T) {
+	a := Asset{ContentType: "text/plain", Body: `{"id":1}`}
+	a.annotate(Asset{})
+
+	assert.Equal(t, `{"id":1}`, a.Pretty)
+	assert.Nil(t, a.Fields)
+}
+
+func TestAsset_annotate_xml(t *testing.T) {
+	a := Asset{ContentType: "application/soap+xml", Body: `<user><name>olaf</name></user>`}
+	a.annotate(Asset{})
+
+	assert.Equal(t, "<user>\n  <name>olaf</name>\n</user>", a.Pretty)
+	assert.Nil(t, a.Fields)
+
+	a = Asset{ContentType: "text/xml", Body: `<user>`}
+	a.annotate(Asset{})
+
+	assert.Equal(t, `<user>`, a.Pretty)
+}
@@ -0,0 +1,126 @@
+package api
+
+import "strings"
+
+// AuthScheme describes how clients authenticate, declared by AUTH metadata, e.g.
+// `AUTH: bearer Tokens are issued by https://auth.example.com` or
+// `AUTH: apiKey header X-API-Key Keys are listed in the dashboard`
+type AuthScheme struct {
+	// Type is bearer, basic, oauth2, apiKey or any other scheme written in the blueprint
+	Type string
+	// In is where credentials are sent, either header or query
+	In string
+	// Param is the header or query parameter carrying credentials, e.g. Authorization
+	Param string
+	// Description is Markdown following the scheme
+	Description string
+}
+
+// authHeaders are header names carrying credentials regardless of AUTH metadata, lowercased
+var authHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+	"api-key":             true,
+	"x-auth-token":        true,
+	"x-access-token":      true,
+}
+
+// IsAuthHeader reports whether header key commonly carries credentials, e.g. Authorization or X-API-Key
+func IsAuthHeader(key string) bool {
+	return authHeaders[strings.ToLower(key)]
+}
+
+// AuthSchemes returns schemes of AUTH metadata in declaration order. Without AUTH metadata, schemes
+// are inferred from credential headers of requests, e.g. `Authorization: Bearer <token>`.
+func (a *API) AuthSchemes() []AuthScheme {
+	var xs []AuthScheme
+
+	for _, m := range a.Metadata {
+		if strings.EqualFold(m.Key, "AUTH") && strings.TrimSpace(m.Value) != "" {
+			xs = append(xs, parseAuthScheme(m.Value))
+		}
+	}
+
+	if len(xs) > 0 {
+		return xs
+	}
+
+	seen := map[AuthScheme]bool{}
+
+	for _, g := range a.ResourceGroups {
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				for _, x := range t.Transactions {
+					for _, h := range x.Request.Headers {
+						s, ok := inferAuthScheme(h)
+						if ok && !seen[s] {
+							seen[s] = true
+							xs = append(xs, s)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return xs
+}
+
+func parseAuthScheme(s string) AuthScheme {
+	typ, rest := nextField(s)
+	x := AuthScheme{Type: normalizeAuthType(typ), In: "header", Param: "Authorization"}
+
+	if x.Type == "apiKey" {
+		in, z := nextField(rest)
+		param, z := nextField(z)
+
+		if (in == "header" || in == "query") && param != "" {
+			x.In, x.Param, rest = in, param, z
+		}
+	}
+
+	x.Description = rest
+	return x
+}
+
+func inferAuthScheme(h Header) (AuthScheme, bool) {
+	if !IsAuthHeader(h.Key) {
+		return AuthScheme{}, false
+	}
+
+	if !strings.EqualFold(h.Key, "Authorization") && !strings.EqualFold(h.Key, "Proxy-Authorization") {
+		return AuthScheme{Type: "apiKey", In: "header", Param: h.Key}, true
+	}
+
+	typ, _ := nextField(h.Value)
+	if typ == "" {
+		return AuthScheme{}, false
+	}
+
+	return AuthScheme{Type: normalizeAuthType(typ), In: "header", Param: h.Key}, true
+}
+
+// normalizeAuthType spells well-known schemes consistently, others are kept as written
+func normalizeAuthType(s string) string {
+	switch strings.ToLower(s) {
+	case "bearer", "basic", "oauth2", "digest":
+		return strings.ToLower(s)
+	case "apikey", "api-key", "api_key":
+		return "apiKey"
+	}
+
+	return s
+}
+
+// nextField splits s into its first whitespace separated field and the trimmed remainder
+func nextField(s string) (string, string) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexAny(s, " \t")
+	if i < 0 {
+		return s, ""
+	}
+
+	return s[:i], strings.TrimSpace(s[i:])
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPI_AuthSchemes(t *testing.T) {
+	b := &API{
+		Metadata: []Metadata{
+			{Key: "HOST", Value: "https://api.example.com"},
+			{Key: "AUTH", Value: "OAuth2  Tokens are issued by https://auth.example.com"},
+			{Key: "AUTH", Value: "api-key header X-API-Key Keys are listed in the dashboard"},
+			{Key: "Auth", Value: "apiKey query api_key"},
+			{Key: "AUTH", Value: "apiKey"},
+		},
+	}
+
+	assert.Equal(t, []AuthScheme{
+		{Type: "oauth2", In: "header", Param: "Authorization", Description: "Tokens are issued by https://auth.example.com"},
+		{Type: "apiKey", In: "header", Param: "X-API-Key", Description: "Keys are listed in the dashboard"},
+		{Type: "apiKey", In: "query", Param: "api_key"},
+		{Type: "apiKey", In: "header", Param: "Authorization"},
+	}, b.AuthSchemes())
+}
+
+func TestAPI_AuthSchemes_inferred(t *testing.T) {
+	req := func(hs ...Header) Transaction {
+		return Transaction{Request: Request{Method: "GET", Headers: hs}}
+	}
+
+	b := &API{
+		ResourceGroups: []ResourceGroup{
+			{
+				Resources: []*Resource{
+					{
+						Transitions: []*Transition{
+							{Transactions: []Transaction{
+								req(Header{Key: "Authorization", Value: "Bearer abc"}, Header{Key: "Accept", Value: "application/json"}),
+								req(Header{Key: "Authorization", Value: "bearer def"}),
+							}},
+							{Transactions: []Transaction{
+								req(Header{Key: "X-Api-Key", Value: "secret"}),
+								req(Header{Key: "Authorization", Value: "Basic dXNlcjpwYXNz"}),
+								req(Header{Key: "Authorization", Value: ""}),
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []AuthScheme{
+		{Type: "bearer", In: "header", Param: "Authorization"},
+		{Type: "apiKey", In: "header", Param: "X-Api-Key"},
+		{Type: "basic", In: "header", Param: "Authorization"},
+	}, b.AuthSchemes())
+
+	assert.Empty(t, (&API{}).AuthSchemes())
+}
+
+func TestIsAuthHeader(t *testing.T) {
+	assert.True(t, IsAuthHeader("Authorization"))
+	assert.True(t, IsAuthHeader("x-api-key"))
+	assert.False(t, IsAuthHeader("Accept"))
+}
@@ -1,10 +1,13 @@
 package api
 
+import "strings"
+
 type API struct {
 	Title          string
 	Description    string
 	Metadata       []Metadata
 	ResourceGroups []ResourceGroup
+	DataStructures []DataStructure
 	Annotations    []Annotation
 }
 
@@ -17,6 +20,16 @@ type ResourceGroup struct {
 	Title       string
 	Description string
 	Resources   []*Resource
+
+	// Sections list Markdown copy and resources in declaration order, keeping prose written
+	// between resources. Description is the first copy.
+	Sections []GroupSection
+}
+
+// GroupSection is either Markdown Copy or Resource of a resource group
+type GroupSection struct {
+	Copy     string
+	Resource *Resource
 }
 
 type Resource struct {
@@ -24,6 +37,17 @@ type Resource struct {
 	Description string
 	Transitions []*Transition
 	Href        Href
+	Attributes  DataStructure
+
+	// Sections list Markdown copy and transitions in declaration order, keeping prose written
+	// between transitions. Description is the first copy.
+	Sections []ResourceSection
+}
+
+// ResourceSection is either Markdown Copy or Transition of a resource
+type ResourceSection struct {
+	Copy       string
+	Transition *Transition
 }
 
 type Transition struct {
@@ -31,6 +55,7 @@ type Transition struct {
 	Description  string
 	Href         Href
 	Transactions []Transaction
+	Attributes   DataStructure
 
 	Permalink string
 	Method    string
@@ -40,6 +65,11 @@ type Transition struct {
 type Asset struct {
 	ContentType string
 	Body        string
+	SourceMaps  []SourceMap
+
+	// Pretty is Body with JSON indented, Fields are annotated from message body schema
+	Pretty string
+	Fields []Field
 }
 
 type Header struct {
@@ -55,6 +85,7 @@ type Request struct {
 	Schema      Asset
 	Headers     []Header
 	ContentType string
+	Attributes  DataStructure
 }
 
 type Response struct {
@@ -63,16 +94,24 @@ type Response struct {
 	Headers     []Header
 	Body        Asset
 	Schema      Asset
+	Attributes  DataStructure
+
+	// Status is the status code as written in blueprint, StatusCode is zero when it is not a number
+	Status string
 }
 
 type Transaction struct {
 	Request  Request
 	Response Response
+
+	Curl   string
+	HTTPie string
 }
 
 type Href struct {
 	Path       string
 	Parameters []Parameter
+	SourceMaps []SourceMap
 }
 
 type Parameter struct {
@@ -85,6 +124,23 @@ type Parameter struct {
 	Members     []string
 }
 
+type DataStructure struct {
+	Name        string
+	Description string
+	Kind        string
+	Members     []Member
+	SourceMaps  []SourceMap
+}
+
+type Member struct {
+	Key         string
+	Kind        string
+	Value       string
+	Description string
+	Required    bool
+	Members     []Member
+}
+
 type Annotation struct {
 	Description string
 	Classes     []string
@@ -96,3 +152,60 @@ type SourceMap struct {
 	Row int
 	Col int
 }
+
+// Severity returns annotation severity, either "warning" or "error"
+func (n Annotation) Severity() string {
+	for _, c := range n.Classes {
+		if c == "warning" {
+			return c
+		}
+	}
+
+	return "error"
+}
+
+// RequestExample is a request body of a transition in one content type, along with transactions
+// sending it, e.g. to render tabs for JSON and form encoded variants
+type RequestExample struct {
+	ContentType  string
+	Request      Request
+	Transactions []Transaction
+}
+
+// RequestsByContentType groups transactions by content type of their request body, taken from the
+// body or its Content-Type header, in order of first appearance. Requests without content type,
+// e.g. of GET, form a group of empty ContentType. Request is the first one of each group.
+func (t *Transition) RequestsByContentType() []RequestExample {
+	var xs []RequestExample
+
+	idx := map[string]int{}
+
+	for _, x := range t.Transactions {
+		ct := requestContentType(x.Request)
+
+		i, ok := idx[ct]
+		if !ok {
+			i = len(xs)
+			idx[ct] = i
+			xs = append(xs, RequestExample{ContentType: ct, Request: x.Request})
+		}
+
+		xs[i].Transactions = append(xs[i].Transactions, x)
+	}
+
+	return xs
+}
+
+func requestContentType(r Request) string {
+	if r.Body.ContentType != "" {
+		return r.Body.ContentType
+	}
+
+	for _, h := range r.Headers {
+		if strings.EqualFold(h.Key, "Content-Type") {
+			return h.Value
+		}
+	}
+
+	return ""
+}
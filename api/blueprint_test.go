@@ -0,0 +1,128 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPI_Meta(t *testing.T) {
+	b := &API{
+		Metadata: []Metadata{
+			{Key: "FORMAT", Value: "1A"},
+			{Key: "HOST", Value: "https://api.example.com"},
+			{Key: "X-Team", Value: "Payments"},
+		},
+	}
+
+	assert.Equal(t, "Payments", b.Meta("X-Team"))
+	assert.Equal(t, "https://api.example.com", b.Host())
+	assert.Equal(t, "", b.Meta("VERSION"))
+}
+
+func TestAPI_SetHost(t *testing.T) {
+	tr := &Transition{
+		Href:         Href{Path: "/users/{id}", Parameters: []Parameter{{Key: "id", Value: "1"}}},
+		Transactions: []Transaction{{Request: Request{Method: "GET"}}},
+	}
+
+	b := &API{
+		Metadata:       []Metadata{{Key: "HOST", Value: "https://api.example.com"}},
+		ResourceGroups: []ResourceGroup{{Resources: []*Resource{{Transitions: []*Transition{tr}}}}},
+	}
+
+	b.SetHost("https://staging.api.example.com/")
+
+	assert.Equal(t, "https://staging.api.example.com/", b.Host())
+	assert.Len(t, b.Metadata, 1)
+	assert.Equal(t, "https://staging.api.example.com/users/{id}", tr.URL)
+	assert.Contains(t, tr.Transactions[0].Curl, "https://staging.api.example.com/users/1")
+	assert.Contains(t, tr.Transactions[0].HTTPie, "https://staging.api.example.com/users/1")
+
+	b = &API{}
+	b.SetHost("http://localhost:8087")
+
+	assert.Equal(t, "http://localhost:8087", b.Host())
+}
+
+func TestTransition_RequestsByContentType(t *testing.T) {
+	json := Request{Method: "POST", Body: Asset{ContentType: "application/json", Body: `{"name": "olaf"}`}}
+	form := Request{Method: "POST", Headers: []Header{{Key: "content-type", Value: "multipart/form-data; boundary=x"}}}
+	xml := Request{Method: "POST", Body: Asset{ContentType: "application/xml", Body: `<user/>`}}
+
+	tr := &Transition{
+		Transactions: []Transaction{
+			{Request: json, Response: Response{StatusCode: 201}},
+			{Request: form, Response: Response{StatusCode: 201}},
+			{Request: json, Response: Response{StatusCode: 422}},
+			{Request: xml, Response: Response{StatusCode: 201}},
+		},
+	}
+
+	xs := tr.RequestsByContentType()
+
+	if assert.Len(t, xs, 3) {
+		assert.Equal(t, "application/json", xs[0].ContentType)
+		assert.Equal(t, json, xs[0].Request)
+		assert.Len(t, xs[0].Transactions, 2)
+		assert.Equal(t, 422, xs[0].Transactions[1].Response.StatusCode)
+
+		assert.Equal(t, "multipart/form-data; boundary=x", xs[1].ContentType)
+		assert.Len(t, xs[1].Transactions, 1)
+
+		assert.Equal(t, "application/xml", xs[2].ContentType)
+	}
+
+	xs = (&Transition{Transactions: []Transaction{{Request: Request{Method: "GET"}}}}).RequestsByContentType()
+
+	if assert.Len(t, xs, 1) {
+		assert.Equal(t, "", xs[0].ContentType)
+	}
+
+	assert.Empty(t, (&Transition{}).RequestsByContentType())
+}
+
+func TestNewAPI_sections(t *testing.T) {
+	el, err := ParseJSON(strings.NewReader(`{"element": "parseResult", "content": [
+	  {"element": "category", "meta": {"classes": ["api"]}, "content": [
+	    {"element": "category", "meta": {"classes": ["resourceGroup"], "title": "Users"}, "content": [
+	      {"element": "copy", "content": "Users sign up by email."},
+	      {"element": "resource", "meta": {"title": "Users"}, "attributes": {"href": "/users"}, "content": [
+	        {"element": "copy", "content": "Collection of users."},
+	        {"element": "transition", "meta": {"title": "List"}},
+	        {"element": "copy", "content": "Creating requires an invitation."},
+	        {"element": "transition", "meta": {"title": "Create"}}
+	      ]},
+	      {"element": "copy", "content": "Deleted users are kept for 30 days."},
+	      {"element": "resource", "meta": {"title": "User"}, "attributes": {"href": "/users/{id}"}}
+	    ]}
+	  ]}
+	]}`))
+	assert.Nil(t, err)
+
+	b, err := NewAPI(el)
+	assert.Nil(t, err)
+
+	g := b.ResourceGroups[0]
+	assert.Equal(t, "Users sign up by email.", g.Description)
+
+	if assert.Len(t, g.Sections, 4) {
+		assert.Equal(t, "Users sign up by email.", g.Sections[0].Copy)
+		assert.Equal(t, g.Resources[0], g.Sections[1].Resource)
+		assert.Equal(t, "Deleted users are kept for 30 days.", g.Sections[2].Copy)
+		assert.Nil(t, g.Sections[2].Resource)
+		assert.Equal(t, "User", g.Sections[3].Resource.Title)
+	}
+
+	r := g.Resources[0]
+	assert.Equal(t, "Collection of users.", r.Description)
+
+	if assert.Len(t, r.Sections, 4) {
+		assert.Equal(t, "List", r.Sections[1].Transition.Title)
+		assert.Equal(t, "Creating requires an invitation.", r.Sections[2].Copy)
+		assert.Equal(t, r.Transitions[1], r.Sections[3].Transition)
+	}
+
+	assert.Empty(t, g.Resources[1].Sections)
+}
@@ -30,6 +30,8 @@ func (b *Element) String() string {
 			return v.String()
 		case reflect.Float64:
 			return strconv.Itoa(int(v.Float()))
+		case reflect.Bool:
+			return strconv.FormatBool(v.Bool())
 		}
 	}
 
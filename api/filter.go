@@ -0,0 +1,42 @@
+package api
+
+import "strings"
+
+// MatchGroup reports whether resource group of title is selected, titles are matched
+// case-insensitively. Every group is selected when only is empty, unless excluded.
+func MatchGroup(title string, only, exclude []string) bool {
+	if len(only) > 0 && !containsFold(only, title) {
+		return false
+	}
+
+	return !containsFold(exclude, title)
+}
+
+// Filter returns a copy of blueprint keeping matching resource groups only. Data structures
+// and metadata are kept, so references within the remaining groups still resolve.
+func Filter(b *API, only, exclude []string) *API {
+	if len(only) == 0 && len(exclude) == 0 {
+		return b
+	}
+
+	x := *b
+	x.ResourceGroups = []ResourceGroup{}
+
+	for _, g := range b.ResourceGroups {
+		if MatchGroup(g.Title, only, exclude) {
+			x.ResourceGroups = append(x.ResourceGroups, g)
+		}
+	}
+
+	return &x
+}
+
+func containsFold(ss []string, s string) bool {
+	for _, x := range ss {
+		if strings.EqualFold(strings.TrimSpace(x), strings.TrimSpace(s)) {
+			return true
+		}
+	}
+
+	return false
+}
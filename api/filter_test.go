@@ -0,0 +1,28 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	b := &API{
+		Title:          "API",
+		ResourceGroups: []ResourceGroup{{Title: "Users"}, {Title: "Messages"}, {Title: "Tasks"}},
+		DataStructures: []DataStructure{{Name: "User"}},
+	}
+
+	x := Filter(b, []string{"users", "Tasks"}, nil)
+	assert.Equal(t, []ResourceGroup{{Title: "Users"}, {Title: "Tasks"}}, x.ResourceGroups)
+	assert.Equal(t, b.DataStructures, x.DataStructures)
+	assert.Len(t, b.ResourceGroups, 3)
+
+	x = Filter(b, nil, []string{"messages"})
+	assert.Equal(t, []ResourceGroup{{Title: "Users"}, {Title: "Tasks"}}, x.ResourceGroups)
+
+	x = Filter(b, []string{"Users", "Messages"}, []string{"Users"})
+	assert.Equal(t, []ResourceGroup{{Title: "Messages"}}, x.ResourceGroups)
+
+	assert.Equal(t, b, Filter(b, nil, nil))
+}
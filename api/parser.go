@@ -50,6 +50,7 @@ func (a *API) digElements(el *Element) {
 			a.digDescription(el)
 			a.digMetadata(el)
 			a.digResourceGroups(el)
+			a.digDataStructures(el)
 			a.digHelperAttributes()
 		}
 	case "annotation":
@@ -65,15 +66,17 @@ func (a *API) digAnnotation(el *Element) {
 			Code:        extractInt("attributes.code", el),
 		}
 
-		n.digSourceMaps(el.Path("attributes.sourceMap"))
+		n.SourceMaps = extractSourceMaps(el.Path("attributes.sourceMap"))
 		a.Annotations = append(a.Annotations, *n)
 	}
 }
 
-func (n *Annotation) digSourceMaps(el *Element) {
+func extractSourceMaps(el *Element) []SourceMap {
+	var ms []SourceMap
+
 	children, err := el.Children()
 	if err != nil {
-		return
+		return nil
 	}
 
 	for _, child := range children {
@@ -88,10 +91,12 @@ func (n *Annotation) digSourceMaps(el *Element) {
 				}
 
 				m := SourceMap{Row: ns[0], Col: ns[1]}
-				n.SourceMaps = append(n.SourceMaps, m)
+				ms = append(ms, m)
 			}
 		}
 	}
+
+	return ms
 }
 
 func (a *API) digTitle(el *Element) {
@@ -130,13 +135,38 @@ func (a *API) digResourceGroups(el *Element) {
 		}
 
 		g.digResources(child)
+		g.digSections(child)
 		a.ResourceGroups = append(a.ResourceGroups, *g)
 	}
 }
 
+func (a *API) digDataStructures(el *Element) {
+	children := filterContentByClass("dataStructures", el)
+
+	for _, child := range filterContentByClass("resourceGroup", el) {
+		for _, r := range filterContentByElement("resource", child) {
+			children = append(children, r)
+		}
+	}
+
+	for _, child := range children {
+		for _, c := range filterContentByElement("dataStructure", child) {
+			d := extractDataStructure(c)
+			if d.Name != "" {
+				a.DataStructures = append(a.DataStructures, d)
+			}
+		}
+	}
+}
+
 func (a *API) Host() string {
+	return a.Meta("HOST")
+}
+
+// Meta returns value of metadata key, e.g. FORMAT, HOST or custom keys like X-Team
+func (a *API) Meta(key string) string {
 	for _, m := range a.Metadata {
-		if m.Key == "HOST" {
+		if m.Key == key {
 			return m.Value
 		}
 	}
@@ -144,6 +174,24 @@ func (a *API) Host() string {
 	return ""
 }
 
+// SetHost overrides HOST metadata, rebuilding URLs and request snippets of every transition
+func (a *API) SetHost(host string) {
+	found := false
+
+	for i := range a.Metadata {
+		if a.Metadata[i].Key == "HOST" {
+			a.Metadata[i].Value = host
+			found = true
+		}
+	}
+
+	if !found {
+		a.Metadata = append(a.Metadata, Metadata{Key: "HOST", Value: host})
+	}
+
+	a.digHelperAttributes()
+}
+
 func (a *API) digHelperAttributes() {
 	for _, g := range a.ResourceGroups {
 		for _, r := range g.Resources {
@@ -151,6 +199,13 @@ func (a *API) digHelperAttributes() {
 				t.Method = requestMethod(*t)
 				t.Permalink = buildPermalink(g, r, t, t.Method)
 				t.URL = buildURL(a.Host(), t, r)
+
+				u := expandURL(t.URL, hrefParameters(t, r))
+
+				for i := range t.Transactions {
+					t.Transactions[i].Curl = buildCurl(u, t.Transactions[i].Request)
+					t.Transactions[i].HTTPie = buildHTTPie(u, t.Transactions[i].Request)
+				}
 			}
 		}
 	}
@@ -173,7 +228,12 @@ func (g *ResourceGroup) digResources(el *Element) {
 				Href:        extractHrefs(c),
 			}
 
+			if ds := filterContentByElement("dataStructure", c); len(ds) > 0 {
+				r.Attributes = extractDataStructure(ds[0])
+			}
+
 			r.digTransitions(c)
+			r.digSections(c)
 
 			cr <- r
 		}(child)
@@ -192,6 +252,22 @@ func (g *ResourceGroup) digResources(el *Element) {
 	g.Resources = rs
 }
 
+func (g *ResourceGroup) digSections(el *Element) {
+	n := 0
+
+	for _, child := range contentChildren(el) {
+		switch child.Path("element").String() {
+		case "copy":
+			g.Sections = append(g.Sections, GroupSection{Copy: child.Path("content").String()})
+		case "resource":
+			if n < len(g.Resources) {
+				g.Sections = append(g.Sections, GroupSection{Resource: g.Resources[n]})
+				n++
+			}
+		}
+	}
+}
+
 func (r *Resource) digTransitions(el *Element) {
 	children := filterContentByElement("transition", el)
 
@@ -200,6 +276,7 @@ func (r *Resource) digTransitions(el *Element) {
 			Title:       child.Path("meta.title").String(),
 			Description: extractCopy(child),
 			Href:        extractHrefs(child),
+			Attributes:  extractAttributes(child.Path("attributes.data")),
 		}
 
 		t.digTransactions(child)
@@ -207,6 +284,22 @@ func (r *Resource) digTransitions(el *Element) {
 	}
 }
 
+func (r *Resource) digSections(el *Element) {
+	n := 0
+
+	for _, child := range contentChildren(el) {
+		switch child.Path("element").String() {
+		case "copy":
+			r.Sections = append(r.Sections, ResourceSection{Copy: child.Path("content").String()})
+		case "transition":
+			if n < len(r.Transitions) {
+				r.Sections = append(r.Sections, ResourceSection{Transition: r.Transitions[n]})
+				n++
+			}
+		}
+	}
+}
+
 func (t *Transition) digTransactions(el *Element) {
 	children := filterContentByElement("httpTransaction", el)
 
@@ -255,11 +348,18 @@ func (x *Transaction) digRequest(child *Element) {
 		if hasClass("messageBodySchema", c) {
 			x.Request.Schema = extractAsset(c)
 		}
+
+		if c.Path("element").String() == "dataStructure" {
+			x.Request.Attributes = extractDataStructure(c)
+		}
 	}
+
+	x.Request.Body.annotate(x.Request.Schema)
 }
 
 func (x *Transaction) digResponse(child *Element) {
 	x.Response.StatusCode = extractInt("attributes.statusCode", child)
+	x.Response.Status = child.Path("attributes.statusCode").String()
 	x.Response.Headers = extractHeaders(child.Path("attributes.headers"))
 	x.Response.Description = extractCopy(child)
 
@@ -276,7 +376,13 @@ func (x *Transaction) digResponse(child *Element) {
 		if hasClass("messageBodySchema", c) {
 			x.Response.Schema = extractAsset(c)
 		}
+
+		if c.Path("element").String() == "dataStructure" {
+			x.Response.Attributes = extractDataStructure(c)
+		}
 	}
+
+	x.Response.Body.annotate(x.Response.Schema)
 }
 
 func extractHeaders(child *Element) (hs []Header) {
@@ -302,11 +408,8 @@ func extractHeaders(child *Element) (hs []Header) {
 }
 
 func extractHrefs(child *Element) (h Href) {
-	href := child.Path("attributes.href")
-
-	if href.Value().IsValid() {
-		h.Path = href.String()
-	}
+	h.Path = extractString("attributes.href", child)
+	h.SourceMaps = extractSourceMaps(child.Path("attributes.href.attributes.sourceMap"))
 
 	contents, err := child.Path("attributes.hrefVariables.content").Children()
 	if err != nil {
@@ -354,11 +457,93 @@ func extractHrefs(child *Element) (h Href) {
 	return
 }
 
+func extractDataStructure(child *Element) DataStructure {
+	el := child.Path("content")
+
+	// Older refract serializes data structure content as a single-element array.
+	if el.Value().Kind() == reflect.Slice {
+		el = el.Index(0)
+	}
+
+	sm := extractSourceMaps(el.Path("meta.id.attributes.sourceMap"))
+	if len(sm) == 0 {
+		sm = extractSourceMaps(el.Path("attributes.sourceMap"))
+	}
+
+	return DataStructure{
+		Name:        extractString("meta.id", el),
+		Description: extractString("meta.description", el),
+		Kind:        el.Path("element").String(),
+		Members:     extractMembers(el),
+		SourceMaps:  sm,
+	}
+}
+
+// extractAttributes returns the data structure of an `Attributes` section, if any
+func extractAttributes(el *Element) DataStructure {
+	if el.Path("element").String() != "dataStructure" {
+		return DataStructure{}
+	}
+
+	return extractDataStructure(el)
+}
+
+func extractMembers(el *Element) (ms []Member) {
+	var children []*Element
+	var err error
+
+	switch el.Path("element").String() {
+	case "enum":
+		children, err = el.Path("attributes.enumerations.content").Children()
+		if err != nil {
+			children, err = el.Path("content").Children()
+		}
+	default:
+		children, err = el.Path("content").Children()
+	}
+
+	if err != nil {
+		return
+	}
+
+	for _, child := range children {
+		if child.Path("element").String() != "member" {
+			ms = append(ms, extractMember(child))
+			continue
+		}
+
+		m := extractMember(child.Path("content.value"))
+		m.Key = child.Path("content.key.content").String()
+		m.Description = extractString("meta.description", child)
+		m.Required = isContains("attributes.typeAttributes", "required", child)
+
+		ms = append(ms, m)
+	}
+
+	return
+}
+
+func extractMember(el *Element) Member {
+	m := Member{Kind: el.Path("element").String()}
+
+	switch m.Kind {
+	case "object", "array", "enum":
+		m.Members = extractMembers(el)
+	case "ref":
+		m.Kind = el.Path("content").String()
+	default:
+		m.Value = el.Path("content").String()
+	}
+
+	return m
+}
+
 func extractAsset(child *Element) (a Asset) {
 	if child.Path("element").String() == "asset" {
 		return Asset{
 			ContentType: child.Path("attributes.contentType").String(),
 			Body:        strUnescapse(child.Path("content").String()),
+			SourceMaps:  extractSourceMaps(child.Path("attributes.sourceMap")),
 		}
 	}
 
@@ -386,12 +571,7 @@ func isContains(key, s string, child *Element) bool {
 }
 
 func extractCopy(el *Element) string {
-	children, err := el.Path("content").Children()
-	if err != nil {
-		return ""
-	}
-
-	for _, child := range children {
+	for _, child := range contentChildren(el) {
 		if child.Path("element").String() == "copy" {
 			return child.Path("content").String()
 		}
@@ -400,6 +580,23 @@ func extractCopy(el *Element) string {
 	return ""
 }
 
+func contentChildren(el *Element) []*Element {
+	children, err := el.Path("content").Children()
+	if err != nil {
+		return nil
+	}
+
+	return children
+}
+
+func extractString(key string, child *Element) string {
+	if s := child.Path(key).String(); s != "" {
+		return s
+	}
+
+	return child.Path(key + ".content").String()
+}
+
 func extractSliceString(key string, child *Element) []string {
 	x := []string{}
 	v := child.Path(key).Value()
@@ -508,6 +705,13 @@ func buildPermalink(g ResourceGroup, r *Resource, t *Transition, fallback string
 
 }
 
+func hrefParameters(t *Transition, r *Resource) []Parameter {
+	ps := make([]Parameter, 0, len(t.Href.Parameters)+len(r.Href.Parameters))
+	ps = append(ps, t.Href.Parameters...)
+
+	return append(ps, r.Href.Parameters...)
+}
+
 func buildURL(host string, t *Transition, r *Resource) string {
 	var path string
 
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// queryShortcuts maps selector segments to refract elements they find, with an optional class
+var queryShortcuts = map[string][2]string{
+	"groups":         {"category", "resourceGroup"},
+	"resources":      {"resource", ""},
+	"transitions":    {"transition", ""},
+	"transactions":   {"httpTransaction", ""},
+	"dataStructures": {"dataStructure", ""},
+	"annotations":    {"annotation", ""},
+}
+
+// Query selects a subtree of the element tree using dot separated selector, e.g.
+// `groups.0.resources.2.transitions`. Segments are object keys, array indexes, or shortcuts
+// listing elements found in the content below: groups, resources, transitions, transactions,
+// dataStructures and annotations. Shortcuts applied to a list search every item of it.
+func (b *Element) Query(selector string) (*Element, error) {
+	o := b.object
+
+	var walked []string
+
+	for _, k := range strings.Split(selector, ".") {
+		if k == "" {
+			continue
+		}
+
+		walked = append(walked, k)
+
+		v, err := querySegment(o, k)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", strings.Join(walked, "."), err)
+		}
+
+		o = v
+	}
+
+	return &Element{o}, nil
+}
+
+func querySegment(o interface{}, k string) (interface{}, error) {
+	if s, ok := queryShortcuts[k]; ok {
+		return findElements(o, s[0], s[1]), nil
+	}
+
+	switch x := o.(type) {
+	case []interface{}:
+		i, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, errors.New("expected array index")
+		}
+
+		if i < 0 || i >= len(x) {
+			return nil, fmt.Errorf("index out of range, there are %d items", len(x))
+		}
+
+		return x[i], nil
+	case map[string]interface{}:
+		v, ok := x[k]
+		if !ok {
+			return nil, errors.New("no such key")
+		}
+
+		return v, nil
+	}
+
+	return nil, errors.New("is not an object or array")
+}
+
+// findElements lists elements named name, having class if not empty, in content of o. Matching
+// elements are not searched further.
+func findElements(o interface{}, name, class string) []interface{} {
+	xs := []interface{}{}
+
+	var cx []interface{}
+
+	switch x := o.(type) {
+	case []interface{}:
+		for _, v := range x {
+			xs = append(xs, findElements(v, name, class)...)
+		}
+
+		return xs
+	case map[string]interface{}:
+		switch c := x["content"].(type) {
+		case []interface{}:
+			cx = c
+		case map[string]interface{}:
+			cx = []interface{}{c}
+		}
+	}
+
+	for _, v := range cx {
+		e := &Element{v}
+
+		if e.Path("element").String() == name && (class == "" || hasClass(class, e)) {
+			xs = append(xs, v)
+			continue
+		}
+
+		xs = append(xs, findElements(v, name, class)...)
+	}
+
+	return xs
+}
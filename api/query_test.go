@@ -0,0 +1,73 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const queryJSON = `{"element": "parseResult", "content": [
+  {"element": "category", "meta": {"classes": ["api"]}, "content": [
+    {"element": "category", "meta": {"classes": ["resourceGroup"], "title": "Users"}, "content": [
+      {"element": "resource", "meta": {"title": "User"}, "attributes": {"href": "/users/{id}"}, "content": [
+        {"element": "transition", "meta": {"title": "Retrieve"}, "content": [
+          {"element": "httpTransaction", "content": [{"element": "httpRequest"}, {"element": "httpResponse"}]}
+        ]},
+        {"element": "transition", "meta": {"title": "Delete"}}
+      ]}
+    ]},
+    {"element": "category", "meta": {"classes": ["resourceGroup"], "title": "Orders"}, "content": [
+      {"element": "resource", "meta": {"title": "Orders"}}
+    ]},
+    {"element": "category", "meta": {"classes": ["dataStructures"]}, "content": [
+      {"element": "dataStructure", "content": {"element": "object", "meta": {"id": "User"}}}
+    ]}
+  ]},
+  {"element": "annotation", "content": "unused"}
+]}`
+
+func TestElement_Query(t *testing.T) {
+	el, err := ParseJSON(strings.NewReader(queryJSON))
+	assert.Nil(t, err)
+
+	x, err := el.Query("groups")
+	assert.Nil(t, err)
+	assert.Len(t, x.Object(), 2)
+
+	x, err = el.Query("groups.0.resources.0.transitions.1.meta.title")
+	assert.Nil(t, err)
+	assert.Equal(t, "Delete", x.String())
+
+	x, err = el.Query("groups.resources")
+	assert.Nil(t, err)
+	assert.Len(t, x.Object(), 2)
+
+	x, err = el.Query("transitions.0.transactions.0.content.1.element")
+	assert.Nil(t, err)
+	assert.Equal(t, "httpResponse", x.String())
+
+	x, err = el.Query("dataStructures.0.content.meta.id")
+	assert.Nil(t, err)
+	assert.Equal(t, "User", x.String())
+
+	x, err = el.Query("annotations.0.content")
+	assert.Nil(t, err)
+	assert.Equal(t, "unused", x.String())
+
+	x, err = el.Query("")
+	assert.Nil(t, err)
+	assert.Equal(t, el.Object(), x.Object())
+
+	_, err = el.Query("groups.2")
+	assert.EqualError(t, err, "groups.2: index out of range, there are 2 items")
+
+	_, err = el.Query("groups.0.title")
+	assert.EqualError(t, err, "groups.0.title: no such key")
+
+	_, err = el.Query("groups.first")
+	assert.EqualError(t, err, "groups.first: expected array index")
+
+	_, err = el.Query("element.0")
+	assert.EqualError(t, err, "element.0: is not an object or array")
+}
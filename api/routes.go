@@ -0,0 +1,148 @@
+package api
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	templateQuery    = regexp.MustCompile(`\{[?&][^}]*\}`)
+	templateVariable = regexp.MustCompile(`\{[^}]*\}`)
+)
+
+// Route is a transaction of a transition, transitions without transactions are
+// listed once with zero StatusCode and nil Transaction.
+type Route struct {
+	Group      string
+	Method     string
+	Path       string
+	URL        string
+	StatusCode int
+
+	Resource    *Resource
+	Transition  *Transition
+	Transaction *Transaction
+}
+
+// Routes lists routes of blueprint in declaration order. Path is the URI template
+// without HOST metadata, e.g. `/users/{id}`, while URL keeps it.
+func Routes(b *API) []Route {
+	rs := []Route{}
+	host := strings.TrimSuffix(b.Host(), "/")
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				x := Route{
+					Group:      g.Title,
+					Method:     t.Method,
+					Path:       strings.TrimPrefix(t.URL, host),
+					URL:        t.URL,
+					Resource:   r,
+					Transition: t,
+				}
+
+				if len(t.Transactions) == 0 {
+					rs = append(rs, x)
+					continue
+				}
+
+				for i := range t.Transactions {
+					n := &t.Transactions[i]
+
+					x.Transaction = n
+					x.StatusCode = n.Response.StatusCode
+
+					if n.Request.Method != "" {
+						x.Method = n.Request.Method
+					}
+
+					rs = append(rs, x)
+				}
+			}
+		}
+	}
+
+	return rs
+}
+
+// MatchRoutes returns routes of method whose path template matches a concrete path, e.g.
+// `/users/1` matches `/users/{id}`. Query of the path is ignored. Routes with fewer
+// variables come first, so `/users/me` prefers `/users/me` over `/users/{id}`.
+func MatchRoutes(b *API, method, p string) []Route {
+	return NewMatcher(b).Match(method, p)
+}
+
+// Matcher matches concrete paths against routes of a blueprint, compiling path templates once
+type Matcher struct {
+	routes   []Route
+	patterns []*regexp.Regexp
+}
+
+// NewMatcher builds Matcher of routes of b
+func NewMatcher(b *API) *Matcher {
+	m := &Matcher{routes: Routes(b)}
+
+	for _, x := range m.routes {
+		m.patterns = append(m.patterns, templatePattern(x.Path))
+	}
+
+	return m
+}
+
+// Match returns routes of method matching path p, see MatchRoutes
+func (m *Matcher) Match(method, p string) []Route {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+
+	p = path.Join("/", p)
+
+	var xs []Route
+
+	for i, x := range m.routes {
+		if strings.EqualFold(x.Method, method) && m.patterns[i].MatchString(p) {
+			xs = append(xs, x)
+		}
+	}
+
+	sort.SliceStable(xs, func(i, j int) bool {
+		return templateVariables(xs[i].Path) < templateVariables(xs[j].Path)
+	})
+
+	return xs
+}
+
+// templatePattern converts path template into regular expression, reserved expansion like
+// `{+path}` may span several segments.
+func templatePattern(t string) *regexp.Regexp {
+	t = path.Join("/", templateQuery.ReplaceAllString(t, ""))
+
+	var bf strings.Builder
+	bf.WriteString("^")
+
+	i := 0
+
+	for _, m := range templateVariable.FindAllStringIndex(t, -1) {
+		bf.WriteString(regexp.QuoteMeta(t[i:m[0]]))
+
+		if strings.HasPrefix(t[m[0]:], "{+") {
+			bf.WriteString(".+")
+		} else {
+			bf.WriteString("[^/]+")
+		}
+
+		i = m[1]
+	}
+
+	bf.WriteString(regexp.QuoteMeta(t[i:]))
+	bf.WriteString("$")
+
+	return regexp.MustCompile(bf.String())
+}
+
+func templateVariables(t string) int {
+	return len(templateVariable.FindAllString(templateQuery.ReplaceAllString(t, ""), -1))
+}
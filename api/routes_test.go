@@ -0,0 +1,83 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutes(t *testing.T) {
+	get := &Transition{
+		Method: "GET",
+		URL:    "https://api.example.com/users/{id}",
+		Transactions: []Transaction{
+			{Request: Request{Method: "GET"}, Response: Response{StatusCode: 200}},
+			{Request: Request{Method: "GET"}, Response: Response{StatusCode: 404}},
+		},
+	}
+	del := &Transition{Method: "DELETE", URL: "https://api.example.com/users/{id}"}
+	res := &Resource{Transitions: []*Transition{get, del}}
+
+	b := &API{
+		Metadata: []Metadata{{Key: "HOST", Value: "https://api.example.com/"}},
+		ResourceGroups: []ResourceGroup{
+			{
+				Title:     "Users",
+				Resources: []*Resource{res},
+			},
+		},
+	}
+
+	assert.Equal(t, []Route{
+		{Group: "Users", Method: "GET", Path: "/users/{id}", URL: get.URL, StatusCode: 200, Resource: res, Transition: get, Transaction: &get.Transactions[0]},
+		{Group: "Users", Method: "GET", Path: "/users/{id}", URL: get.URL, StatusCode: 404, Resource: res, Transition: get, Transaction: &get.Transactions[1]},
+		{Group: "Users", Method: "DELETE", Path: "/users/{id}", URL: del.URL, Resource: res, Transition: del},
+	}, Routes(b))
+}
+
+func TestMatchRoutes(t *testing.T) {
+	transition := func(method, u string) *Transition {
+		return &Transition{
+			Method:       method,
+			URL:          "https://api.example.com" + u,
+			Transactions: []Transaction{{Request: Request{Method: method}, Response: Response{StatusCode: 200}}},
+		}
+	}
+
+	b := &API{
+		Metadata: []Metadata{{Key: "HOST", Value: "https://api.example.com"}},
+		ResourceGroups: []ResourceGroup{
+			{
+				Resources: []*Resource{
+					{
+						Transitions: []*Transition{
+							transition("GET", "/users{?page}"),
+							transition("GET", "/users/{id}"),
+							transition("GET", "/users/me"),
+							transition("DELETE", "/users/{id}"),
+							transition("GET", "/files/{+path}"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	paths := func(xs []Route) []string {
+		ps := []string{}
+
+		for _, x := range xs {
+			ps = append(ps, x.Method+" "+x.Path)
+		}
+
+		return ps
+	}
+
+	assert.Equal(t, []string{"GET /users{?page}"}, paths(MatchRoutes(b, "GET", "/users?page=2")))
+	assert.Equal(t, []string{"GET /users/{id}"}, paths(MatchRoutes(b, "get", "/users/1")))
+	assert.Equal(t, []string{"GET /users/me", "GET /users/{id}"}, paths(MatchRoutes(b, "GET", "/users/me/")))
+	assert.Equal(t, []string{"DELETE /users/{id}"}, paths(MatchRoutes(b, "DELETE", "/users/1")))
+	assert.Equal(t, []string{"GET /files/{+path}"}, paths(MatchRoutes(b, "GET", "/files/a/b.txt")))
+	assert.Empty(t, MatchRoutes(b, "GET", "/users/1/posts"))
+	assert.Empty(t, MatchRoutes(b, "POST", "/users"))
+}
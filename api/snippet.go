@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var uriTemplatePattern = regexp.MustCompile(`\{([?&+#./;]?)([^}]+)\}`)
+
+// expandURL expands URI template using parameter example values,
+// variables without example value are left untouched.
+func expandURL(u string, params []Parameter) string {
+	values := map[string]string{}
+
+	for _, p := range params {
+		v := p.Value
+		if v == "" {
+			v = p.Default
+		}
+
+		if _, ok := values[p.Key]; !ok && v != "" {
+			values[p.Key] = v
+		}
+	}
+
+	return uriTemplatePattern.ReplaceAllStringFunc(u, func(s string) string {
+		m := uriTemplatePattern.FindStringSubmatch(s)
+		op, names := m[1], strings.Split(m[2], ",")
+
+		switch op {
+		case "?", "&":
+			xs := []string{}
+
+			for _, n := range names {
+				if v, ok := values[n]; ok {
+					xs = append(xs, url.QueryEscape(n)+"="+url.QueryEscape(v))
+				}
+			}
+
+			if len(xs) == 0 {
+				return ""
+			}
+
+			if op == "?" && strings.Contains(strings.SplitN(u, s, 2)[0], "?") {
+				op = "&"
+			}
+
+			return op + strings.Join(xs, "&")
+		case "":
+			xs := []string{}
+
+			for _, n := range names {
+				v, ok := values[n]
+				if !ok {
+					return s
+				}
+
+				xs = append(xs, url.PathEscape(v))
+			}
+
+			return strings.Join(xs, ",")
+		case "+":
+			xs := []string{}
+
+			for _, n := range names {
+				v, ok := values[n]
+				if !ok {
+					return s
+				}
+
+				xs = append(xs, v)
+			}
+
+			return strings.Join(xs, ",")
+		}
+
+		return s
+	})
+}
+
+func buildCurl(u string, r Request) string {
+	if r.Method == "" {
+		return ""
+	}
+
+	s := "curl"
+
+	if r.Method != "GET" {
+		s += " -X " + r.Method
+	}
+
+	xs := []string{s + " " + shellQuote(u)}
+
+	for _, h := range r.Headers {
+		xs = append(xs, "-H "+shellQuote(h.Key+": "+h.Value))
+	}
+
+	if r.Body.Body != "" {
+		xs = append(xs, "--data-raw "+shellQuote(r.Body.Body))
+	}
+
+	return strings.Join(xs, " \\\n  ")
+}
+
+// buildHTTPie builds HTTPie command, the body is passed raw through stdin
+// so JSON examples are sent as they are written.
+func buildHTTPie(u string, r Request) string {
+	if r.Method == "" {
+		return ""
+	}
+
+	s := "http"
+
+	if r.Method != "GET" || r.Body.Body != "" {
+		s += " " + r.Method
+	}
+
+	xs := []string{s + " " + shellQuote(u)}
+
+	for _, h := range r.Headers {
+		xs = append(xs, shellQuote(h.Key+":"+h.Value))
+	}
+
+	if r.Body.Body != "" {
+		xs = append(xs, "<<< "+shellQuote(r.Body.Body))
+	}
+
+	return strings.Join(xs, " \\\n  ")
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
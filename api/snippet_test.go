@@ -0,0 +1,56 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandURL(t *testing.T) {
+	ps := []Parameter{
+		{Key: "id", Value: "a b"},
+		{Key: "page", Value: "1"},
+		{Key: "q", Value: "x&y"},
+		{Key: "limit", Default: "10"},
+	}
+
+	assert.Equal(t, "https://api.example.com/users/a%20b", expandURL("https://api.example.com/users/{id}", ps))
+	assert.Equal(t, "/users?page=1&q=x%26y&limit=10", expandURL("/users{?page,q,limit,sort}", ps))
+	assert.Equal(t, "/users?type=all&page=1", expandURL("/users?type=all{&page}", ps))
+	assert.Equal(t, "/users?type=all&page=1", expandURL("/users?type=all{?page}", ps))
+	assert.Equal(t, "/users", expandURL("/users{?sort}", ps))
+	assert.Equal(t, "/users/{name}", expandURL("/users/{name}", ps))
+}
+
+func TestBuildCurl(t *testing.T) {
+	assert.Equal(t, "curl 'https://api.example.com/users'", buildCurl("https://api.example.com/users", Request{Method: "GET"}))
+	assert.Empty(t, buildCurl("/users", Request{}))
+
+	r := Request{
+		Method:  "POST",
+		Headers: []Header{{Key: "Content-Type", Value: "application/json"}, {Key: "X-Quote", Value: "it's"}},
+		Body:    Asset{Body: `{"name": "O'Brien", "cmd": "$(rm -rf /)"}`},
+	}
+
+	assert.Equal(t, `curl -X POST 'https://api.example.com/users' \
+  -H 'Content-Type: application/json' \
+  -H 'X-Quote: it'\''s' \
+  --data-raw '{"name": "O'\''Brien", "cmd": "$(rm -rf /)"}'`, buildCurl("https://api.example.com/users", r))
+}
+
+func TestBuildHTTPie(t *testing.T) {
+	assert.Equal(t, "http 'https://api.example.com/users?page=1&q=x%26y'", buildHTTPie("https://api.example.com/users?page=1&q=x%26y", Request{Method: "GET"}))
+	assert.Equal(t, "http DELETE 'https://api.example.com/users/1'", buildHTTPie("https://api.example.com/users/1", Request{Method: "DELETE"}))
+	assert.Empty(t, buildHTTPie("/users", Request{}))
+
+	r := Request{
+		Method:  "POST",
+		Headers: []Header{{Key: "Content-Type", Value: "application/json"}, {Key: "X-Quote", Value: "it's"}},
+		Body:    Asset{Body: `{"name": "O'Brien"}`},
+	}
+
+	assert.Equal(t, `http POST 'https://api.example.com/users' \
+  'Content-Type:application/json' \
+  'X-Quote:it'\''s' \
+  <<< '{"name": "O'\''Brien"}'`, buildHTTPie("https://api.example.com/users", r))
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// IndentXML indents XML document s by two spaces, dropping whitespace between elements. Empty
+// elements are self-closed, namespace prefixes, comments and declarations are kept as written.
+func IndentXML(s string) (string, error) {
+	d := xml.NewDecoder(strings.NewReader(strings.TrimSpace(s)))
+
+	var bf bytes.Buffer
+	var stack []string
+
+	open := false   // start tag is written without its closing `>`
+	inline := false // element has text, its end tag follows on the same line
+
+	newline := func() {
+		if bf.Len() > 0 {
+			bf.WriteString("\n" + strings.Repeat("  ", len(stack)))
+		}
+	}
+
+	for {
+		t, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := t.(xml.EndElement); open && !ok {
+			bf.WriteString(">")
+			open = false
+		}
+
+		switch x := t.(type) {
+		case xml.StartElement:
+			newline()
+			bf.WriteString("<" + xmlName(x.Name))
+
+			for _, a := range x.Attr {
+				bf.WriteString(" " + xmlName(a.Name) + `="`)
+				xml.EscapeText(&bf, []byte(a.Value))
+				bf.WriteString(`"`)
+			}
+
+			stack = append(stack, xmlName(x.Name))
+			open, inline = true, false
+		case xml.EndElement:
+			n := len(stack) - 1
+			if n < 0 || stack[n] != xmlName(x.Name) {
+				return "", fmt.Errorf("unexpected end element </%s>", xmlName(x.Name))
+			}
+
+			stack = stack[:n]
+
+			switch {
+			case open:
+				bf.WriteString("/>")
+			case inline:
+				bf.WriteString("</" + xmlName(x.Name) + ">")
+			default:
+				newline()
+				bf.WriteString("</" + xmlName(x.Name) + ">")
+			}
+
+			open, inline = false, false
+		case xml.CharData:
+			if len(bytes.TrimSpace(x)) == 0 {
+				continue
+			}
+
+			xml.EscapeText(&bf, bytes.TrimSpace(x))
+			inline = true
+		case xml.Comment:
+			newline()
+			bf.WriteString("<!--" + string(x) + "-->")
+		case xml.ProcInst:
+			newline()
+			bf.WriteString("<?" + x.Target + " " + string(x.Inst) + "?>")
+		case xml.Directive:
+			newline()
+			bf.WriteString("<!" + string(x) + ">")
+		}
+	}
+
+	if len(stack) > 0 {
+		return "", fmt.Errorf("unclosed element <%s>", stack[len(stack)-1])
+	}
+
+	return bf.String(), nil
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+
+	return n.Space + ":" + n.Local
+}
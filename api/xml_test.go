@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndentXML(t *testing.T) {
+	s, err := IndentXML(`<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body>  <!-- user -->
+<user id="1"><name>Olaf &amp; Elsa</name><tags/></user></soap:Body></soap:Envelope>`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">
+  <soap:Body>
+    <!-- user -->
+    <user id="1">
+      <name>Olaf &amp; Elsa</name>
+      <tags/>
+    </user>
+  </soap:Body>
+</soap:Envelope>`, s)
+
+	_, err = IndentXML(`<user><name>`)
+	assert.EqualError(t, err, "unclosed element <name>")
+
+	_, err = IndentXML(`<user></name>`)
+	assert.EqualError(t, err, "unexpected end element </name>")
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/loader"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/bukalapak/snowboard/render"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var treeIndex = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API Documentation</title>
+</head>
+<body>
+<h1>API Documentation</h1>
+<ul>
+{{range .}}{{if not .Err}}<li><a href="{{.Output}}">{{.Title}}</a> <small>{{.Input}}</small></li>
+{{end}}{{end}}</ul>
+</body>
+</html>
+`))
+
+// treeResult is the outcome of rendering a blueprint of a tree, paths are slash separated and relative
+type treeResult struct {
+	Input  string
+	Output string
+	Title  string
+	Err    error
+}
+
+// findBlueprints lists `*.apib` files under dir in lexical order. Hidden directories are skipped,
+// as well as partials included by another blueprint.
+func findBlueprints(dir string) ([]string, error) {
+	var fs []string
+
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() && p != dir && strings.HasPrefix(fi.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		if !fi.IsDir() && filepath.Ext(p) == ".apib" {
+			fs = append(fs, p)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	included := map[string]bool{}
+
+	for _, f := range fs {
+		for _, x := range loader.Includes(f) {
+			included[x] = true
+		}
+	}
+
+	var xs []string
+
+	for _, f := range fs {
+		if abs, err := filepath.Abs(f); err == nil && included[abs] {
+			continue
+		}
+
+		xs = append(xs, f)
+	}
+
+	return xs, nil
+}
+
+// renderHTMLTree renders every blueprint under dir concurrently into a mirrored tree of out,
+// e.g. `users/API.apib` into `users/API.html`, along with index.html linking them.
+// Every blueprint is reported once all of them are rendered.
+func renderHTMLTree(c *cli.Context, dir, out, tplFile string) error {
+	if out == "" {
+		return fmt.Errorf("Recursive rendering requires output directory, use -o flag")
+	}
+
+	fs, err := findBlueprints(dir)
+	if err != nil {
+		return err
+	}
+
+	if len(fs) == 0 {
+		return fmt.Errorf("%s: no API blueprint found", dir)
+	}
+
+	tf, err := readTemplate(tplFile)
+	if err != nil {
+		return err
+	}
+
+	opt, err := htmlOptions(c)
+	if err != nil {
+		return err
+	}
+
+	tpl, err := opt.Compile(string(tf))
+	if err != nil {
+		return err
+	}
+
+	jobs := c.Int("jobs")
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	rs := make([]treeResult, len(fs))
+	ch := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for n := 0; n < jobs; n++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range ch {
+				rs[i] = renderTreeFile(c, tpl, dir, out, fs[i])
+			}
+		}()
+	}
+
+	for i := range fs {
+		ch <- i
+	}
+
+	close(ch)
+	wg.Wait()
+
+	if err = writeTreeIndex(filepath.Join(out, "index.html"), rs); err != nil {
+		return err
+	}
+
+	return reportTree(c, rs)
+}
+
+func renderTreeFile(c *cli.Context, tpl *render.Template, dir, out, input string) treeResult {
+	rel, err := filepath.Rel(dir, input)
+	if err != nil {
+		return treeResult{Input: input, Err: err}
+	}
+
+	output := strings.TrimSuffix(rel, filepath.Ext(rel)) + ".html"
+	x := treeResult{Input: filepath.ToSlash(rel), Output: filepath.ToSlash(output), Title: filepath.ToSlash(rel)}
+
+	bp, err := snowboard.Load(input)
+	if err != nil {
+		x.Err = err
+		return x
+	}
+
+	overrideHost(c, bp)
+
+	only, exclude := groupFilters(c)
+	bp = api.Filter(bp, only, exclude)
+
+	if bp.Title != "" {
+		x.Title = bp.Title
+	}
+
+	name := filepath.Join(out, output)
+
+	if err = os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		x.Err = err
+		return x
+	}
+
+	of, err := os.Create(name)
+	if err != nil {
+		x.Err = err
+		return x
+	}
+	defer of.Close()
+
+	x.Err = tpl.Execute(of, bp)
+	return x
+}
+
+func writeTreeIndex(name string, rs []treeResult) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	of, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer of.Close()
+
+	return treeIndex.Execute(of, rs)
+}
+
+// reportTree prints outcome of every blueprint, it fails when any of them failed
+func reportTree(c *cli.Context, rs []treeResult) error {
+	var n int
+
+	for _, x := range rs {
+		if x.Err != nil {
+			n++
+			fmt.Fprintln(c.App.ErrWriter, paint(c.App.ErrWriter, colorRed, fmt.Sprintf("FAIL %s: %s", x.Input, x.Err)))
+			continue
+		}
+
+		if !c.Bool("q") {
+			fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("OK   %s -> %s", x.Input, x.Output)))
+		}
+	}
+
+	if n > 0 {
+		return fmt.Errorf("%d of %d blueprints failed", n, len(rs))
+	}
+
+	return nil
+}
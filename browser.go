@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// browserWait bounds waiting for the server to accept connections before opening browser
+const browserWait = 5 * time.Second
+
+// openBrowser opens server listening on bind in the default browser once it accepts connections.
+// Without display, e.g. in headless CI, or when the server fails to start, nothing is opened.
+func openBrowser(c *cli.Context, bind string, secure bool) {
+	u := browserURL(bind, secure)
+
+	if !waitListening(u.Host, browserWait) {
+		return
+	}
+
+	cmd := browserCommand(u.String())
+	if cmd == nil {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorDim, fmt.Sprintf("No browser available, open %s manually", u)))
+		return
+	}
+
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorDim, fmt.Sprintf("Opening browser failed: %s, open %s manually", err, u)))
+		return
+	}
+
+	go cmd.Wait()
+}
+
+// browserURL returns URL of server listening on bind, unspecified hosts are reached via localhost
+func browserURL(bind string, secure bool) *url.URL {
+	host, port, err := net.SplitHostPort(bind)
+	if err != nil {
+		host, port = bind, "80"
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	u := &url.URL{Scheme: "http", Host: net.JoinHostPort(host, port), Path: "/"}
+
+	if secure {
+		u.Scheme = "https"
+	}
+
+	return u
+}
+
+func waitListening(addr string, d time.Duration) bool {
+	deadline := time.Now().Add(d)
+
+	for time.Now().Before(deadline) {
+		if conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond); err == nil {
+			conn.Close()
+			return true
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return false
+}
+
+// browserCommand returns command opening u in the default browser: open on macOS, start on
+// Windows and xdg-open elsewhere. It is nil on CI and on systems without display.
+func browserCommand(u string) *exec.Cmd {
+	if os.Getenv("CI") != "" {
+		return nil
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", u)
+	case "windows":
+		return exec.Command("cmd", "/c", "start", "", u)
+	}
+
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return nil
+	}
+
+	if _, err := exec.LookPath("xdg-open"); err != nil {
+		return nil
+	}
+
+	return exec.Command("xdg-open", u)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	cli "gopkg.in/urfave/cli.v1"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// defaultConfig is loaded from working directory when --config is not given
+const defaultConfig = "snowboard.yml"
+
+// config holds defaults from configuration file, command flags are keyed by command name then flag name
+type config struct {
+	Input    string                            `yaml:"input"`
+	Commands map[string]map[string]interface{} `yaml:",inline"`
+}
+
+var cfg config
+
+// loadConfig reads configuration file, a missing default file is not an error
+func loadConfig(name string) error {
+	if name == "" {
+		if _, err := os.Stat(defaultConfig); os.IsNotExist(err) {
+			return nil
+		}
+
+		name = defaultConfig
+	}
+
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	if err = yaml.Unmarshal(b, &cfg); err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	return nil
+}
+
+// applyConfig sets command flags from configuration file unless given on command line
+func applyConfig(c *cli.Context) error {
+	for name, v := range cfg.Commands[c.Command.Name] {
+		if c.IsSet(name) {
+			continue
+		}
+
+		var xs []interface{}
+
+		switch z := v.(type) {
+		case []interface{}:
+			xs = z
+		default:
+			xs = []interface{}{z}
+		}
+
+		for _, x := range xs {
+			if err := c.Set(name, fmt.Sprint(x)); err != nil {
+				return exitError(fmt.Sprintf("Invalid config %s.%s: %s", c.Command.Name, name, err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// inputArg returns the first argument, or input from configuration file when none given
+func inputArg(c *cli.Context) string {
+	if c.NArg() == 0 {
+		return cfg.Input
+	}
+
+	return c.Args().Get(0)
+}
+
+// inputArgs returns the arguments, or input from configuration file when none given
+func inputArgs(c *cli.Context) []string {
+	if c.NArg() == 0 && cfg.Input != "" {
+		return []string{cfg.Input}
+	}
+
+	return c.Args()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/bukalapak/snowboard/api"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/bukalapak/snowboard/schema"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// conformResponse validates JSON document against the response schema of the route matching
+// method and path, `-` reads the document from stdin. Without status, the first successful
+// response having a schema is used.
+func conformResponse(c *cli.Context, input, method, path, name string, status int) error {
+	bp, err := snowboard.Load(input)
+	if err != nil {
+		return err
+	}
+
+	rs := api.MatchRoutes(bp, method, path)
+	if len(rs) == 0 {
+		return fmt.Errorf("no route matches %s %s", method, path)
+	}
+
+	x, ok := responseSchema(rs, status)
+	if !ok {
+		if status > 0 {
+			return fmt.Errorf("%s %s has no schema for response %d", rs[0].Method, rs[0].Path, status)
+		}
+
+		return fmt.Errorf("%s %s has no response schema", rs[0].Method, rs[0].Path)
+	}
+
+	var b []byte
+
+	if name == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	errs, err := schema.Validate([]byte(x.Transaction.Response.Schema.Body), b)
+	if err != nil {
+		return err
+	}
+
+	route := fmt.Sprintf("%s %s %d", x.Method, x.Path, x.StatusCode)
+
+	if len(errs) == 0 {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("PASS %s: %s", name, route)))
+		return nil
+	}
+
+	fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorRed, fmt.Sprintf("FAIL %s: %s", name, route)))
+
+	for _, e := range errs {
+		fmt.Fprintf(c.App.Writer, "  %s\n", e)
+	}
+
+	return fmt.Errorf("%s does not conform to %s, %d errors found", name, route, len(errs))
+}
+
+// responseSchema picks the route whose response has a schema, among those of the most specific
+// matching path template
+func responseSchema(rs []api.Route, status int) (api.Route, bool) {
+	var xs []api.Route
+
+	for _, x := range rs {
+		if x.Path != rs[0].Path || x.Transaction == nil || x.Transaction.Response.Schema.Body == "" {
+			continue
+		}
+
+		if status > 0 && x.StatusCode != status {
+			continue
+		}
+
+		xs = append(xs, x)
+	}
+
+	for _, x := range xs {
+		if x.StatusCode >= 200 && x.StatusCode < 300 {
+			return x, true
+		}
+	}
+
+	if len(xs) > 0 {
+		return xs[0], true
+	}
+
+	return api.Route{}, false
+}
@@ -0,0 +1,117 @@
+// Package coverage reports endpoints of an API blueprint exercised by an access log
+package coverage
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+// logPattern captures method and request URI of a line in common or combined log format, e.g.
+// `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /users/1 HTTP/1.0" 200 2326`
+var logPattern = regexp.MustCompile(`^\S+ \S+ .*?\[[^\]]+\] "(\S+) (\S+)[^"]*"`)
+
+// Endpoint is a documented method and path template with the number of requests hitting it
+type Endpoint struct {
+	Group  string `json:"group"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Hits   int    `json:"hits"`
+}
+
+// Report holds endpoints of a blueprint in declaration order along with coverage of the log
+type Report struct {
+	Endpoints []Endpoint `json:"endpoints"`
+	Covered   int        `json:"covered"`
+	Total     int        `json:"total"`
+	Percent   float64    `json:"percent"`
+	Unmatched int        `json:"unmatched"`
+	Skipped   int        `json:"skipped"`
+}
+
+// Missing returns endpoints without hits
+func (r Report) Missing() []Endpoint {
+	var xs []Endpoint
+
+	for _, e := range r.Endpoints {
+		if e.Hits == 0 {
+			xs = append(xs, e)
+		}
+	}
+
+	return xs
+}
+
+// Compute matches requests of access log r against endpoints of b, after removing base path
+// prefix, e.g. `/api/v2`. Requests matching no endpoint are counted as Unmatched, lines not
+// in common or combined log format as Skipped.
+func Compute(b *api.API, r io.Reader, base string) (Report, error) {
+	x := Report{}
+	m := api.NewMatcher(b)
+	idx := map[string]int{}
+
+	for _, z := range api.Routes(b) {
+		k := z.Method + " " + z.Path
+		if _, ok := idx[k]; ok {
+			continue
+		}
+
+		idx[k] = len(x.Endpoints)
+		x.Endpoints = append(x.Endpoints, Endpoint{Group: z.Group, Method: z.Method, Path: z.Path})
+	}
+
+	base = "/" + strings.Trim(base, "/")
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for s.Scan() {
+		ms := logPattern.FindStringSubmatch(s.Text())
+		if ms == nil {
+			if strings.TrimSpace(s.Text()) != "" {
+				x.Skipped++
+			}
+
+			continue
+		}
+
+		p := ms[2]
+		if base != "/" {
+			if p != base && !strings.HasPrefix(p, base+"/") && !strings.HasPrefix(p, base+"?") {
+				x.Unmatched++
+				continue
+			}
+
+			p = strings.TrimPrefix(p, base)
+		}
+
+		rs := m.Match(ms[1], p)
+		if len(rs) == 0 {
+			x.Unmatched++
+			continue
+		}
+
+		x.Endpoints[idx[rs[0].Method+" "+rs[0].Path]].Hits++
+	}
+
+	if err := s.Err(); err != nil {
+		return x, err
+	}
+
+	x.Total = len(x.Endpoints)
+
+	for _, e := range x.Endpoints {
+		if e.Hits > 0 {
+			x.Covered++
+		}
+	}
+
+	if x.Total > 0 {
+		x.Percent = float64(x.Covered) * 100 / float64(x.Total)
+	}
+
+	return x, nil
+}
@@ -0,0 +1,77 @@
+package coverage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/coverage"
+	"github.com/stretchr/testify/assert"
+)
+
+const accessLog = `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /api/users?page=2 HTTP/1.0" 200 2326
+127.0.0.1 - - [10/Oct/2000:13:55:37 -0700] "GET /api/users/1 HTTP/1.1" 200 51 "https://example.com/" "curl/7.64.1"
+127.0.0.1 - - [10/Oct/2000:13:55:38 -0700] "GET /api/users/me HTTP/1.1" 200 51 "-" "curl/7.64.1"
+127.0.0.1 - - [10/Oct/2000:13:55:39 -0700] "GET /api/users/1 HTTP/1.1" 404 12
+127.0.0.1 - - [10/Oct/2000:13:55:40 -0700] "GET /api/orders HTTP/1.1" 404 12
+127.0.0.1 - - [10/Oct/2000:13:55:41 -0700] "GET /health HTTP/1.1" 200 2
+
+not an access log line
+`
+
+func TestCompute(t *testing.T) {
+	transition := func(method, u string, codes ...int) *api.Transition {
+		x := &api.Transition{Method: method, URL: "https://api.example.com" + u}
+
+		for _, n := range codes {
+			x.Transactions = append(x.Transactions, api.Transaction{Request: api.Request{Method: method}, Response: api.Response{StatusCode: n}})
+		}
+
+		return x
+	}
+
+	b := &api.API{
+		Metadata: []api.Metadata{{Key: "HOST", Value: "https://api.example.com"}},
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Title: "Users",
+				Resources: []*api.Resource{
+					{
+						Transitions: []*api.Transition{
+							transition("GET", "/users{?page}", 200),
+							transition("GET", "/users/{id}", 200, 404),
+							transition("GET", "/users/me", 200),
+							transition("DELETE", "/users/{id}"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	x, err := coverage.Compute(b, strings.NewReader(accessLog), "/api/")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []coverage.Endpoint{
+		{Group: "Users", Method: "GET", Path: "/users{?page}", Hits: 1},
+		{Group: "Users", Method: "GET", Path: "/users/{id}", Hits: 2},
+		{Group: "Users", Method: "GET", Path: "/users/me", Hits: 1},
+		{Group: "Users", Method: "DELETE", Path: "/users/{id}"},
+	}, x.Endpoints)
+
+	assert.Equal(t, 3, x.Covered)
+	assert.Equal(t, 4, x.Total)
+	assert.Equal(t, 75.0, x.Percent)
+	assert.Equal(t, 2, x.Unmatched)
+	assert.Equal(t, 1, x.Skipped)
+	assert.Equal(t, []coverage.Endpoint{{Group: "Users", Method: "DELETE", Path: "/users/{id}"}}, x.Missing())
+
+	x, err = coverage.Compute(b, strings.NewReader(accessLog), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, x.Covered)
+	assert.Equal(t, 6, x.Unmatched)
+
+	x, err = coverage.Compute(&api.API{}, strings.NewReader(""), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, x.Percent)
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ungrouped titles changes of endpoints outside any resource group
+const ungrouped = "(ungrouped)"
+
+// Changelog renders changes as Markdown document titled title, grouped by resource group in
+// order of appearance. Every group lists Added and Removed endpoints, then Changed ones with
+// their parameter and response changes. Breaking changes are marked.
+func Changelog(cs []Change, title string) string {
+	var bf bytes.Buffer
+
+	fmt.Fprintf(&bf, "# %s\n", title)
+
+	if len(cs) == 0 {
+		bf.WriteString("\nNo changes.\n")
+		return bf.String()
+	}
+
+	var groups []string
+
+	byGroup := map[string][]Change{}
+
+	for _, c := range cs {
+		if _, ok := byGroup[c.Group]; !ok {
+			groups = append(groups, c.Group)
+		}
+
+		byGroup[c.Group] = append(byGroup[c.Group], c)
+	}
+
+	for _, g := range groups {
+		name := g
+		if name == "" {
+			name = ungrouped
+		}
+
+		fmt.Fprintf(&bf, "\n## %s\n", name)
+
+		var added, removed, changed []string
+
+		for _, c := range byGroup[g] {
+			switch {
+			case c.Item == "endpoint" && c.Kind == Added:
+				added = append(added, changelogEntry(c, ""))
+			case c.Item == "endpoint" && c.Kind == Removed:
+				removed = append(removed, changelogEntry(c, ""))
+			default:
+				changed = append(changed, changelogEntry(c, changeDetail(c)))
+			}
+		}
+
+		changelogSection(&bf, "Added", added)
+		changelogSection(&bf, "Removed", removed)
+		changelogSection(&bf, "Changed", changed)
+	}
+
+	return bf.String()
+}
+
+func changelogSection(bf *bytes.Buffer, name string, xs []string) {
+	if len(xs) == 0 {
+		return
+	}
+
+	fmt.Fprintf(bf, "\n### %s\n\n", name)
+
+	for _, x := range xs {
+		fmt.Fprintf(bf, "- %s\n", x)
+	}
+}
+
+func changelogEntry(c Change, detail string) string {
+	s := fmt.Sprintf("`%s`", c.Endpoint)
+
+	if detail != "" {
+		s += ": " + detail
+	}
+
+	if c.Breaking {
+		s += " **(breaking)**"
+	}
+
+	return s
+}
+
+// changeDetail describes change of a parameter or response, e.g. `added parameter page`
+func changeDetail(c Change) string {
+	if c.Kind != Changed {
+		return c.Kind + " " + c.Item
+	}
+
+	if c.Detail == "schema" {
+		return c.Item + " schema changed"
+	}
+
+	return c.Item + " " + c.Detail
+}
@@ -0,0 +1,255 @@
+// Package diff compares two API blueprints and reports breaking changes
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+// Kinds of change
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change describes a single difference between two blueprints
+type Change struct {
+	Group    string `json:"group,omitempty"`
+	Kind     string `json:"kind"`
+	Endpoint string `json:"endpoint"`
+	Item     string `json:"item"`
+	Detail   string `json:"detail,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+type endpoint struct {
+	Group      string
+	Key        string
+	Parameters []api.Parameter
+	Schemas    map[int]string
+}
+
+var queryPattern = regexp.MustCompile(`\{[?&][^}]*\}`)
+
+// Compare lists changes needed to turn blueprint a into b
+func Compare(a, b *api.API) []Change {
+	xs := endpoints(a)
+	ys := endpoints(b)
+
+	cs := []Change{}
+
+	for _, x := range xs {
+		y := find(ys, x.Key)
+
+		if y == nil {
+			cs = append(cs, Change{Group: x.Group, Kind: Removed, Endpoint: x.Key, Item: "endpoint", Breaking: true})
+			continue
+		}
+
+		cs = append(cs, withGroup(y.Group, compareParameters(x, y))...)
+		cs = append(cs, withGroup(y.Group, compareSchemas(x, y))...)
+	}
+
+	for _, y := range ys {
+		if find(xs, y.Key) == nil {
+			cs = append(cs, Change{Group: y.Group, Kind: Added, Endpoint: y.Key, Item: "endpoint"})
+		}
+	}
+
+	return cs
+}
+
+func withGroup(g string, cs []Change) []Change {
+	for i := range cs {
+		cs[i].Group = g
+	}
+
+	return cs
+}
+
+// Breaking reports whether any of changes is potentially breaking
+func Breaking(cs []Change) bool {
+	for _, c := range cs {
+		if c.Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compareParameters(x, y *endpoint) []Change {
+	cs := []Change{}
+
+	for _, p := range x.Parameters {
+		item := "parameter " + p.Key
+		q := findParameter(y.Parameters, p.Key)
+
+		if q == nil {
+			cs = append(cs, Change{Kind: Removed, Endpoint: x.Key, Item: item, Breaking: p.Required})
+			continue
+		}
+
+		if !p.Required && q.Required {
+			cs = append(cs, Change{Kind: Changed, Endpoint: x.Key, Item: item, Detail: "optional -> required", Breaking: true})
+		}
+
+		if p.Required && !q.Required {
+			cs = append(cs, Change{Kind: Changed, Endpoint: x.Key, Item: item, Detail: "required -> optional"})
+		}
+
+		if p.Kind != q.Kind {
+			cs = append(cs, Change{Kind: Changed, Endpoint: x.Key, Item: item, Detail: fmt.Sprintf("type %s -> %s", p.Kind, q.Kind)})
+		}
+	}
+
+	for _, q := range y.Parameters {
+		if findParameter(x.Parameters, q.Key) == nil {
+			cs = append(cs, Change{Kind: Added, Endpoint: x.Key, Item: "parameter " + q.Key, Breaking: q.Required})
+		}
+	}
+
+	return cs
+}
+
+func compareSchemas(x, y *endpoint) []Change {
+	cs := []Change{}
+
+	for _, s := range statusCodes(x.Schemas, y.Schemas) {
+		item := fmt.Sprintf("response %d", s)
+		a, inX := x.Schemas[s]
+		b, inY := y.Schemas[s]
+
+		switch {
+		case !inY:
+			cs = append(cs, Change{Kind: Removed, Endpoint: x.Key, Item: item})
+		case !inX:
+			cs = append(cs, Change{Kind: Added, Endpoint: x.Key, Item: item})
+		case a != b:
+			cs = append(cs, Change{Kind: Changed, Endpoint: x.Key, Item: item, Detail: "schema"})
+		}
+	}
+
+	return cs
+}
+
+func endpoints(b *api.API) []*endpoint {
+	var xs []*endpoint
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				x := &endpoint{
+					Group:      g.Title,
+					Key:        transitionMethod(t) + " " + transitionPath(r, t),
+					Parameters: parameters(r, t),
+					Schemas:    map[int]string{},
+				}
+
+				for _, n := range t.Transactions {
+					if _, ok := x.Schemas[n.Response.StatusCode]; !ok {
+						x.Schemas[n.Response.StatusCode] = normalize(n.Response.Schema.Body)
+					}
+				}
+
+				xs = append(xs, x)
+			}
+		}
+	}
+
+	return xs
+}
+
+func transitionMethod(t *api.Transition) string {
+	if t.Method != "" {
+		return t.Method
+	}
+
+	for _, n := range t.Transactions {
+		if n.Request.Method != "" {
+			return n.Request.Method
+		}
+	}
+
+	return ""
+}
+
+func transitionPath(r *api.Resource, t *api.Transition) string {
+	p := r.Href.Path
+	if t.Href.Path != "" {
+		p = t.Href.Path
+	}
+
+	return queryPattern.ReplaceAllString(p, "")
+}
+
+// parameters merges resource and transition parameters, the latter takes precedence
+func parameters(r *api.Resource, t *api.Transition) []api.Parameter {
+	var ps []api.Parameter
+
+	for _, p := range t.Href.Parameters {
+		ps = append(ps, p)
+	}
+
+	for _, p := range r.Href.Parameters {
+		if findParameter(ps, p.Key) == nil {
+			ps = append(ps, p)
+		}
+	}
+
+	return ps
+}
+
+func normalize(s string) string {
+	var v interface{}
+
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return strings.TrimSpace(s)
+	}
+
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func statusCodes(a, b map[int]string) []int {
+	var xs []int
+
+	for k := range a {
+		xs = append(xs, k)
+	}
+
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			xs = append(xs, k)
+		}
+	}
+
+	sort.Ints(xs)
+	return xs
+}
+
+func find(xs []*endpoint, key string) *endpoint {
+	for _, x := range xs {
+		if x.Key == key {
+			return x
+		}
+	}
+
+	return nil
+}
+
+func findParameter(ps []api.Parameter, key string) *api.Parameter {
+	for i := range ps {
+		if ps[i].Key == key {
+			return &ps[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/diff"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAPI(params []api.Parameter, schema string, transitions ...*api.Transition) *api.API {
+	ts := []*api.Transition{
+		{
+			Method: "GET",
+			Transactions: []api.Transaction{
+				{Response: api.Response{StatusCode: 200, Schema: api.Asset{Body: schema}}},
+			},
+		},
+	}
+
+	return &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Href:        api.Href{Path: "/users/{id}{?fields}", Parameters: params},
+						Transitions: append(ts, transitions...),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := newAPI([]api.Parameter{
+		{Key: "id", Kind: "number", Required: true},
+		{Key: "fields", Kind: "string"},
+	}, `{"type": "object"}`, &api.Transition{Method: "DELETE"})
+
+	b := newAPI([]api.Parameter{
+		{Key: "id", Kind: "string", Required: true},
+		{Key: "expand", Kind: "string"},
+	}, `{"type":"object"}`, &api.Transition{Method: "PUT"})
+
+	cs := diff.Compare(a, b)
+	assert.Equal(t, []diff.Change{
+		{Kind: diff.Changed, Endpoint: "GET /users/{id}", Item: "parameter id", Detail: "type number -> string"},
+		{Kind: diff.Removed, Endpoint: "GET /users/{id}", Item: "parameter fields"},
+		{Kind: diff.Added, Endpoint: "GET /users/{id}", Item: "parameter expand"},
+		{Kind: diff.Removed, Endpoint: "DELETE /users/{id}", Item: "endpoint", Breaking: true},
+		{Kind: diff.Added, Endpoint: "PUT /users/{id}", Item: "endpoint"},
+	}, cs)
+	assert.True(t, diff.Breaking(cs))
+}
+
+func TestCompare_parameters(t *testing.T) {
+	a := newAPI([]api.Parameter{{Key: "id", Required: true}, {Key: "fields"}}, "")
+	b := newAPI([]api.Parameter{{Key: "fields", Required: true}, {Key: "token", Required: true}}, "")
+
+	cs := diff.Compare(a, b)
+	assert.Equal(t, []diff.Change{
+		{Kind: diff.Removed, Endpoint: "GET /users/{id}", Item: "parameter id", Breaking: true},
+		{Kind: diff.Changed, Endpoint: "GET /users/{id}", Item: "parameter fields", Detail: "optional -> required", Breaking: true},
+		{Kind: diff.Added, Endpoint: "GET /users/{id}", Item: "parameter token", Breaking: true},
+	}, cs)
+}
+
+func TestCompare_schemas(t *testing.T) {
+	a := newAPI(nil, `{"type": "object"}`)
+	b := newAPI(nil, `{"type": "array"}`)
+	b.ResourceGroups[0].Resources[0].Transitions[0].Transactions = append(b.ResourceGroups[0].Resources[0].Transitions[0].Transactions, api.Transaction{
+		Response: api.Response{StatusCode: 404},
+	})
+
+	cs := diff.Compare(a, b)
+	assert.Equal(t, []diff.Change{
+		{Kind: diff.Changed, Endpoint: "GET /users/{id}", Item: "response 200", Detail: "schema"},
+		{Kind: diff.Added, Endpoint: "GET /users/{id}", Item: "response 404"},
+	}, cs)
+	assert.False(t, diff.Breaking(cs))
+}
+
+func TestCompare_same(t *testing.T) {
+	cs := diff.Compare(newAPI(nil, ""), newAPI(nil, ""))
+	assert.Empty(t, cs)
+	assert.False(t, diff.Breaking(cs))
+}
+
+func TestChangelog(t *testing.T) {
+	a := newAPI([]api.Parameter{{Key: "id", Kind: "number", Required: true}, {Key: "fields"}}, `{"type": "object"}`, &api.Transition{Method: "DELETE"})
+	a.ResourceGroups[0].Title = "Users"
+
+	b := newAPI([]api.Parameter{{Key: "id", Kind: "number"}, {Key: "token", Required: true}}, `{"type": "array"}`, &api.Transition{Method: "PUT"})
+	b.ResourceGroups[0].Title = "Users"
+	b.ResourceGroups = append(b.ResourceGroups, api.ResourceGroup{
+		Resources: []*api.Resource{
+			{Href: api.Href{Path: "/health"}, Transitions: []*api.Transition{{Method: "GET"}}},
+		},
+	})
+
+	assert.Equal(t, "# v2.0.0\n"+
+		"\n## Users\n"+
+		"\n### Added\n\n"+
+		"- `PUT /users/{id}`\n"+
+		"\n### Removed\n\n"+
+		"- `DELETE /users/{id}` **(breaking)**\n"+
+		"\n### Changed\n\n"+
+		"- `GET /users/{id}`: parameter id required -> optional\n"+
+		"- `GET /users/{id}`: removed parameter fields\n"+
+		"- `GET /users/{id}`: added parameter token **(breaking)**\n"+
+		"- `GET /users/{id}`: response 200 schema changed\n"+
+		"\n## (ungrouped)\n"+
+		"\n### Added\n\n"+
+		"- `GET /health`\n", diff.Changelog(diff.Compare(a, b), "v2.0.0"))
+
+	assert.Equal(t, "# Changelog\n\nNo changes.\n", diff.Changelog(nil, "Changelog"))
+}
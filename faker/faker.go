@@ -0,0 +1,77 @@
+// Package faker generates values of `{{faker.<name>}}` directives in response examples
+package faker
+
+import (
+	"crypto/rand"
+	"fmt"
+	mrand "math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pattern matches `{{faker.<name>}}` directives, capturing the name
+var Pattern = regexp.MustCompile(`\{\{\s*faker\.(\w+)\s*\}\}`)
+
+var (
+	firstNames = []string{"Ada", "Alan", "Barbara", "Dennis", "Edsger", "Grace", "Ken", "Linus", "Margaret", "Rob"}
+	lastNames  = []string{"Hopper", "Knuth", "Lamport", "Liskov", "Lovelace", "Pike", "Ritchie", "Thompson", "Torvalds", "Turing"}
+)
+
+// Generators generates values of directives by name
+var Generators = map[string]func() string{
+	"uuid":   fakeUUID,
+	"name":   fakeName,
+	"email":  fakeEmail,
+	"number": fakeNumber,
+	"now":    fakeNow,
+}
+
+// Expand substitutes directives with fresh values, unknown directives are left untouched
+func Expand(body string) string {
+	return Pattern.ReplaceAllStringFunc(body, func(s string) string {
+		if fn, ok := Generators[Pattern.FindStringSubmatch(s)[1]]; ok {
+			return fn()
+		}
+
+		return s
+	})
+}
+
+// Check fails on the first directive Generators does not generate
+func Check(body string) error {
+	for _, m := range Pattern.FindAllStringSubmatch(body, -1) {
+		if _, ok := Generators[m[1]]; !ok {
+			return fmt.Errorf("unknown faker directive: %s", m[0])
+		}
+	}
+
+	return nil
+}
+
+func fakeUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func fakeName() string {
+	return firstNames[mrand.Intn(len(firstNames))] + " " + lastNames[mrand.Intn(len(lastNames))]
+}
+
+func fakeEmail() string {
+	return strings.ToLower(strings.Replace(fakeName(), " ", ".", 1)) + "@example.com"
+}
+
+func fakeNumber() string {
+	return strconv.Itoa(mrand.Intn(1000))
+}
+
+func fakeNow() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
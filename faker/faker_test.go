@@ -0,0 +1,18 @@
+package faker_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	s := faker.Expand(`{"id": "{{faker.uuid}}", "name": "{{ faker.name }}", "x": "{{faker.unknown}}"}`)
+	assert.Regexp(t, `^\{"id": "[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}", "name": "\w+ \w+", "x": "\{\{faker\.unknown\}\}"\}$`, s)
+}
+
+func TestCheck(t *testing.T) {
+	assert.Nil(t, faker.Check(`{"id": "{{faker.uuid}}", "at": "{{faker.now}}"}`))
+	assert.EqualError(t, faker.Check(`{"id": "{{faker.uuid}}", "city": "{{ faker.city }}"}`), "unknown faker directive: {{ faker.city }}")
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// liveReloadPath streams reload events to browsers viewing served documentation
+const liveReloadPath = "/__livereload"
+
+// liveReloadInterval is how often input and template are polled for changes
+const liveReloadInterval = 500 * time.Millisecond
+
+const liveReloadScript = `<script>new EventSource("` + liveReloadPath + `").onmessage = function() { location.reload() }</script>`
+
+// liveReload broadcasts reload events to connected browsers using server-sent events
+type liveReload struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+}
+
+func newLiveReload() *liveReload {
+	return &liveReload{clients: map[chan struct{}]bool{}}
+}
+
+func (l *liveReload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan struct{}, 1)
+
+	l.mu.Lock()
+	l.clients[ch] = true
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		delete(l.clients, ch)
+		l.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	for {
+		select {
+		case <-ch:
+			fmt.Fprint(w, "data: reload\n\n")
+			f.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// Reload notifies every connected browser, slow ones already having a pending event are skipped
+func (l *liveReload) Reload() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for ch := range l.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// injectLiveReload adds the client script before closing body tag, or at the end when there is none
+func injectLiveReload(b []byte) []byte {
+	s := []byte(liveReloadScript)
+
+	i := bytes.LastIndex(b, []byte("</body>"))
+	if i < 0 {
+		return append(b, s...)
+	}
+
+	z := make([]byte, 0, len(b)+len(s))
+	z = append(z, b[:i]...)
+	z = append(z, s...)
+
+	return append(z, b[i:]...)
+}
+
+// watchHTML calls regenerate whenever input, its seeds and includes, template or its partials
+// change, then tells browsers to reload. Changes within --debounce coalesce into one regeneration,
+// seeds and includes are listed again after every regeneration.
+func watchHTML(c *cli.Context, input, tplFile string, l *liveReload, regenerate func() error) {
+	noteRemoteIncludes(c, []string{input})
+
+	dir := c.String("template-dir")
+	fs := watchedFiles([]string{input})
+	last := htmlModTimes(fs, tplFile, dir)
+
+	t := time.NewTicker(liveReloadInterval)
+	defer t.Stop()
+
+	for range t.C {
+		mt := htmlModTimes(fs, tplFile, dir)
+		if sameModTimes(last, mt) {
+			continue
+		}
+
+		last = settle(c.Duration("debounce"), mt, func() map[string]time.Time {
+			return htmlModTimes(fs, tplFile, dir)
+		})
+
+		// seeds and includes may have been added or removed, mod times are taken before regenerating
+		// so changes made meanwhile are picked up by the next poll
+		next := watchedFiles([]string{input})
+		nt := htmlModTimes(next, tplFile, dir)
+
+		if err := regenerate(); err != nil {
+			fmt.Fprintln(c.App.ErrWriter, paint(c.App.ErrWriter, colorRed, fmt.Sprintf("[%s] Documentation regeneration failed: %s", time.Now().Format(time.RFC3339), err)))
+			continue
+		}
+
+		fs, last = next, nt
+
+		l.Reload()
+	}
+}
+
+// htmlModTimes returns modification times of watched files fs, template and its partials
+func htmlModTimes(fs []string, tplFile, partialDir string) map[string]time.Time {
+	xs := append([]string{tplFile}, fs...)
+
+	if partialDir != "" {
+		ps, _ := filepath.Glob(filepath.Join(partialDir, "*.html"))
+		xs = append(xs, ps...)
+	}
+
+	return modTimes(xs)
+}
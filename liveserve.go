@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bukalapak/snowboard/loader"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// liveHTML renders documentation on request, reusing the last page until input, its seeds and
+// includes, template or partials change
+type liveHTML struct {
+	c       *cli.Context
+	input   string
+	tplFile string
+	live    bool
+
+	mu   sync.Mutex
+	fs   []string
+	mt   map[string]time.Time
+	page []byte
+}
+
+func newLiveHTML(c *cli.Context, input, tplFile string, live bool) *liveHTML {
+	return &liveHTML{c: c, input: input, tplFile: tplFile, live: live}
+}
+
+// render returns the cached page, parsing and rendering again when modification times differ.
+// URL input has none, it is fetched on every request. Failures are not cached, so fixing the
+// blueprint is picked up by the next request.
+func (h *liveHTML) render() ([]byte, time.Time, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.fs == nil {
+		h.fs = watchedFiles([]string{h.input})
+	}
+
+	mt := htmlModTimes(h.fs, h.tplFile, h.c.String("template-dir"))
+	if h.page != nil && !loader.IsURL(h.input) && sameModTimes(h.mt, mt) {
+		return h.page, latestModTime(mt), nil
+	}
+
+	// seeds and includes may have been added or removed. Mod times are taken before loading, so
+	// changes made while rendering are picked up by the next request.
+	fs := watchedFiles([]string{h.input})
+	mt = htmlModTimes(fs, h.tplFile, h.c.String("template-dir"))
+
+	bp, err := loadHTML(h.c, h.input)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	tf, err := readTemplate(h.tplFile)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	opt, err := htmlOptions(h.c)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var bf bytes.Buffer
+
+	if err = opt.HTML(string(tf), &bf, bp); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	h.fs, h.mt, h.page = fs, mt, bf.Bytes()
+
+	return h.page, latestModTime(mt), nil
+}
+
+func (h *liveHTML) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, mt, err := h.render()
+	if err != nil {
+		fmt.Fprintln(h.c.App.ErrWriter, paint(h.c.App.ErrWriter, colorRed, fmt.Sprintf("[%s] Documentation rendering failed: %s", time.Now().Format(time.RFC3339), err)))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if h.live {
+		b = injectLiveReload(b)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha1.Sum(b)))
+	http.ServeContent(w, r, "index.html", mt, bytes.NewReader(b))
+}
+
+func latestModTime(m map[string]time.Time) time.Time {
+	var t time.Time
+
+	for _, x := range m {
+		if x.After(t) {
+			t = x
+		}
+	}
+
+	return t
+}
@@ -3,52 +3,203 @@ package loader
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig"
+	"github.com/bukalapak/snowboard/faker"
 	"github.com/imdario/mergo"
 	"github.com/pkg/errors"
 )
 
+// Stdin is the input name for reading API blueprint from standard input
+const Stdin = "-"
+
+// HTTPClient fetches API blueprint given as http:// or https:// URL
+var HTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// HTTPHeader is sent along every URL request, e.g. for authorization
+var HTTPHeader = http.Header{}
+
+// RemoteTimeout bounds fetching a remote include, e.g. `<!-- include(https://example.com/errors.apib) -->`
+var RemoteTimeout = 10 * time.Second
+
+// RemoteCacheTTL is how long a fetched remote include is reused before fetching it again. When
+// fetching fails, the expired copy is used instead.
+var RemoteCacheTTL = 5 * time.Minute
+
+type remoteEntry struct {
+	b  []byte
+	at time.Time
+}
+
+var remoteCache = struct {
+	sync.Mutex
+	m map[string]remoteEntry
+}{m: map[string]remoteEntry{}}
+
 type loader struct {
-	name    string
-	baseDir string
-	seeds   []string
+	ctx      context.Context
+	name     string
+	baseDir  string
+	baseURL  *url.URL
+	seeds    []string
+	includes []string
+	bodies   []string
+}
+
+// IsURL reports whether name is an http:// or https:// URL
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
 }
 
 func newLoader(name string) *loader {
-	d := &loader{name: name}
+	d := &loader{ctx: context.Background(), name: name}
 	d.detectBaseDir()
 
 	return d
 }
 
 func (d *loader) detectBaseDir() {
+	if IsURL(d.name) {
+		if u, err := url.Parse(d.name); err == nil {
+			d.baseURL = u
+		}
+
+		return
+	}
+
+	if d.name == Stdin {
+		if wd, err := os.Getwd(); err == nil {
+			d.baseDir = wd
+		}
+
+		return
+	}
+
 	abs, err := filepath.Abs(filepath.Dir(d.name))
 	if err == nil {
 		d.baseDir = abs
 	}
 }
 
-func (d *loader) partial(name string) string {
-	b, err := d.read(name)
+func (d *loader) partial(name string) (string, error) {
+	ss := []string{}
+
+	for _, n := range d.expand(name) {
+		b, err := d.read(n)
+		if err != nil {
+			continue
+		}
+
+		z, err := d.bodyFiles(string(b), n)
+		if err != nil {
+			return "", err
+		}
+
+		ss = append(ss, z)
+	}
+
+	return strings.Join(ss, "\n"), nil
+}
+
+// expand resolves glob pattern of local partial into sorted names relative to base directory,
+// e.g. `partials/*.apib`. Other names are kept as is.
+func (d *loader) expand(name string) []string {
+	if d.baseURL != nil || IsURL(name) || !strings.ContainsAny(name, "*?[") {
+		return []string{name}
+	}
+
+	ms, err := filepath.Glob(filepath.Join(d.baseDir, name))
 	if err != nil {
-		return ""
+		return []string{name}
+	}
+
+	ns := []string{}
+
+	for _, m := range ms {
+		if r, err := filepath.Rel(d.baseDir, m); err == nil {
+			ns = append(ns, filepath.ToSlash(r))
+		}
 	}
 
-	return string(b)
+	sort.Strings(ns)
+
+	return ns
 }
 
 func (d *loader) read(name string) ([]byte, error) {
-	fname := filepath.Join(d.baseDir, name)
-	return ioutil.ReadFile(fname)
+	if IsURL(name) {
+		return fetchRemote(d.ctx, name, d.sameHost(name))
+	}
+
+	if d.baseURL != nil {
+		u, err := d.baseURL.Parse(name)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := fetchContext(d.ctx, u.String(), HTTPHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		return readAll(f)
+	}
+
+	f, err := os.Open(filepath.Join(d.baseDir, name))
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(f)
+}
+
+func readAll(f io.ReadCloser) ([]byte, error) {
+	r, err := decompress(f)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// decompress transparently gunzips f when it starts with gzip magic bytes, e.g. `.apib.gz` files
+func decompress(f io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(f)
+
+	if b, err := br.Peek(2); err != nil || b[0] != 0x1f || b[1] != 0x8b {
+		return readCloser{br, f}, nil
+	}
+
+	z, err := gzip.NewReader(br)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return readCloser{z, f}, nil
 }
 
 func (d *loader) unmarshal(name string) (data map[string]interface{}, err error) {
@@ -114,11 +265,190 @@ func (d *loader) convert(s string) string {
 		return ""
 	}
 
+	if format != `{%s}` {
+		d.includes = append(d.includes, d.expand(rs[1])...)
+	}
+
 	return fmt.Sprintf(format, rs[1])
 }
 
+var bodyFilePattern = regexp.MustCompile(`^(\s*)[+*-] Body \(file: (.+)\)\s*$`)
+
+// bodyFile inlines the file referenced by `+ Body (file: examples/user.json)`, indented as the
+// body of the list item. Files are resolved like partials, relative to partial from when the
+// reference is inside one.
+func (d *loader) bodyFile(s, from string) (string, error) {
+	rs := bodyFilePattern.FindStringSubmatch(s)
+	indent, name := rs[1], relativeTo(from, strings.TrimSpace(rs[2]))
+
+	b, err := d.read(name)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("%s -> %s", d.name, name))
+	}
+
+	d.bodies = append(d.bodies, name)
+
+	cs := []string{indent + "+ Body", ""}
+
+	for _, x := range strings.Split(strings.TrimRight(string(b), "\r\n"), "\n") {
+		if strings.TrimSpace(x) != "" {
+			x = indent + "        " + x
+		}
+
+		cs = append(cs, x)
+	}
+
+	return strings.Join(append(cs, ""), "\n"), nil
+}
+
+// bodyFiles inlines body files referenced by partial from
+func (d *loader) bodyFiles(s, from string) (string, error) {
+	cs := strings.Split(s, "\n")
+
+	for i, c := range cs {
+		if !bodyFilePattern.MatchString(c) {
+			continue
+		}
+
+		z, err := d.bodyFile(c, from)
+		if err != nil {
+			return "", err
+		}
+
+		cs[i] = z
+	}
+
+	return strings.Join(cs, "\n"), nil
+}
+
+// relativeTo resolves name referenced by partial from against the partial, e.g. `user.json` of
+// `partials/users.apib` is `partials/user.json`. Names of the blueprint itself, with empty from,
+// and URLs are kept as is.
+func relativeTo(from, name string) string {
+	if from == "" || IsURL(name) || path.IsAbs(name) {
+		return name
+	}
+
+	if IsURL(from) {
+		u, err := url.Parse(from)
+		if err != nil {
+			return name
+		}
+
+		r, err := u.Parse(name)
+		if err != nil {
+			return name
+		}
+
+		return r.String()
+	}
+
+	return path.Join(path.Dir(from), name)
+}
+
+func (d *loader) open() (io.ReadCloser, error) {
+	var f io.ReadCloser
+	var err error
+
+	switch {
+	case d.name == Stdin:
+		f = ioutil.NopCloser(os.Stdin)
+	case IsURL(d.name):
+		f, err = fetchContext(d.ctx, d.name, HTTPHeader)
+	default:
+		f, err = os.Open(d.name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decompress(f)
+}
+
+// sameHost reports whether URL u is on the host of URL input, so HTTPHeader may be sent along
+func (d *loader) sameHost(u string) bool {
+	if d.baseURL == nil {
+		return false
+	}
+
+	x, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(x.Host, d.baseURL.Host)
+}
+
+// fetchContext requests u with headers h
+func fetchContext(ctx context.Context, u string, h http.Header) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+
+	for k, vs := range h {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	res, err := HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		res.Body.Close()
+		return nil, errors.New(res.Status)
+	}
+
+	return res.Body, nil
+}
+
+// fetchRemote returns content of remote include u, cached for RemoteCacheTTL. HTTPHeader, which
+// may carry credentials of the input URL, is only sent when auth is set, i.e. u is on its host.
+func fetchRemote(ctx context.Context, u string, auth bool) ([]byte, error) {
+	remoteCache.Lock()
+	x, ok := remoteCache.m[u]
+	remoteCache.Unlock()
+
+	if ok && time.Since(x.at) < RemoteCacheTTL {
+		return x.b, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, RemoteTimeout)
+	defer cancel()
+
+	h := http.Header{}
+	if auth {
+		h = HTTPHeader
+	}
+
+	f, err := fetchContext(ctx, u, h)
+	if err == nil {
+		var b []byte
+
+		if b, err = readAll(f); err == nil {
+			remoteCache.Lock()
+			remoteCache.m[u] = remoteEntry{b: b, at: time.Now()}
+			remoteCache.Unlock()
+
+			return b, nil
+		}
+	}
+
+	if ok {
+		return x.b, nil
+	}
+
+	return nil, err
+}
+
 func (d *loader) parse() (string, error) {
-	f, err := os.Open(d.name)
+	f, err := d.open()
 	if err != nil {
 		return "", errors.Wrap(err, d.name)
 	}
@@ -131,6 +461,13 @@ func (d *loader) parse() (string, error) {
 		switch {
 		case strings.HasPrefix(scanner.Text(), "<!--"):
 			cs = append(cs, d.convert(scanner.Text()))
+		case bodyFilePattern.MatchString(scanner.Text()):
+			s, err := d.bodyFile(scanner.Text(), "")
+			if err != nil {
+				return "", err
+			}
+
+			cs = append(cs, s)
 		default:
 			cs = append(cs, scanner.Text())
 		}
@@ -151,6 +488,20 @@ func join(ss []interface{}, s string) string {
 	return strings.Join(xs, s)
 }
 
+// fakerDirectives keeps `{{faker.<name>}}` mock directives of s intact, unknown names included, as
+// they are expanded or rejected by the mock server
+func fakerDirectives(s string) func() map[string]string {
+	return func() map[string]string {
+		m := map[string]string{}
+
+		for _, x := range faker.Pattern.FindAllStringSubmatch(s, -1) {
+			m[x[1]] = "{{faker." + x[1] + "}}"
+		}
+
+		return m
+	}
+}
+
 func process(s string, data interface{}, funcMap template.FuncMap) ([]byte, error) {
 	tmpl, err := template.New("apib").Funcs(sprig.TxtFuncMap()).Funcs(funcMap).Parse(s)
 	if err != nil {
@@ -167,9 +518,17 @@ func process(s string, data interface{}, funcMap template.FuncMap) ([]byte, erro
 	return z.Bytes(), nil
 }
 
-// Load loads API blueprint from file as bytes
+// Load loads API blueprint from file as bytes, use Stdin as name to read from standard input.
+// Partials and seeds of standard input are resolved from working directory, while those of
+// http:// or https:// URL are resolved against the URL.
 func Load(name string) ([]byte, error) {
+	return LoadContext(context.Background(), name)
+}
+
+// LoadContext is Load cancelling requests of URL input and remote includes when ctx is done
+func LoadContext(ctx context.Context, name string) ([]byte, error) {
 	d := newLoader(name)
+	d.ctx = ctx
 
 	s, err := d.parse()
 	if err != nil {
@@ -181,7 +540,7 @@ func Load(name string) ([]byte, error) {
 		return nil, err
 	}
 
-	b, err := process(s, data, template.FuncMap{"partial": d.partial})
+	b, err := process(s, data, template.FuncMap{"partial": d.partial, "faker": fakerDirectives(s)})
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +549,7 @@ func Load(name string) ([]byte, error) {
 	funcMap := template.FuncMap{
 		"join":   join,
 		"upcase": strings.ToUpper,
+		"faker":  fakerDirectives(string(b)),
 	}
 
 	b, err = process(string(b), data, funcMap)
@@ -200,13 +560,174 @@ func Load(name string) ([]byte, error) {
 	return b, nil
 }
 
-// Seeds lists filenames of API blueprint's seeds.
+// Seeds lists filenames of API blueprint's seeds, along with body files referenced by `+ Body (file: ...)`.
+// Standard input can only be read once, so it has no seeds.
 func Seeds(name string) []string {
+	if name == Stdin {
+		return []string{}
+	}
+
+	d := newLoader(name)
+
+	if _, err := d.parse(); err != nil {
+		return []string{}
+	}
+
+	// body files of local partials are listed while reading them
+	for _, n := range d.includes {
+		if d.baseURL == nil && !IsURL(n) {
+			d.partial(n)
+		}
+	}
+
+	return append(d.seeds, d.bodies...)
+}
+
+// Includes lists paths of files included by API blueprint, glob patterns are expanded.
+// Only local files are listed, as URL and standard input have no stable path to watch, see
+// RemoteIncludes for included URLs.
+func Includes(name string) []string {
+	if name == Stdin || IsURL(name) {
+		return []string{}
+	}
+
 	d := newLoader(name)
 
 	if _, err := d.parse(); err != nil {
 		return []string{}
 	}
 
-	return d.seeds
+	ps := []string{}
+
+	for _, n := range d.includes {
+		if !IsURL(n) {
+			ps = append(ps, filepath.Join(d.baseDir, n))
+		}
+	}
+
+	return ps
+}
+
+// RemoteIncludes lists http:// and https:// URLs included by API blueprint. They are fetched
+// on every load, subject to RemoteCacheTTL, but cannot be watched for changes.
+func RemoteIncludes(name string) []string {
+	if name == Stdin {
+		return []string{}
+	}
+
+	d := newLoader(name)
+
+	if _, err := d.parse(); err != nil {
+		return []string{}
+	}
+
+	us := []string{}
+
+	for _, n := range d.includes {
+		if IsURL(n) {
+			us = append(us, n)
+		}
+	}
+
+	return us
+}
+
+var includePattern = regexp.MustCompile(`<!-- (?:include|partial)\((.+?)\) -->|\{\{\s*partial\s+"([^"]+)"\s*\}\}`)
+
+// Bundle loads API blueprint with its includes inlined recursively, while seeds and template
+// expressions are kept intact. Includes are resolved from the blueprint like Load does,
+// circular includes are reported along with their path, e.g. `API.apib -> a.apib -> API.apib`.
+func Bundle(name string) ([]byte, error) {
+	d := newLoader(name)
+
+	f, err := d.open()
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	top := name
+	if name != Stdin {
+		top = path.Base(filepath.ToSlash(name))
+	}
+
+	return d.bundle(b, []string{top})
+}
+
+func (d *loader) bundle(b []byte, stack []string) ([]byte, error) {
+	var err error
+
+	z := includePattern.ReplaceAllFunc(b, func(m []byte) []byte {
+		if err != nil {
+			return m
+		}
+
+		sm := includePattern.FindSubmatch(m)
+		name := string(sm[1])
+		if name == "" {
+			name = string(sm[2])
+		}
+
+		cs := [][]byte{}
+
+		for _, n := range d.expand(name) {
+			if !IsURL(n) {
+				n = path.Clean(n)
+			}
+			next := append(append([]string{}, stack...), n)
+
+			for _, x := range stack {
+				if x == n {
+					err = fmt.Errorf("circular include: %s", strings.Join(next, " -> "))
+					return m
+				}
+			}
+
+			c, e := d.read(n)
+			if e != nil {
+				err = errors.Wrap(e, strings.Join(next, " -> "))
+				return m
+			}
+
+			c = rebaseBodyFiles(c, n)
+
+			c, e = d.bundle(c, next)
+			if e != nil {
+				err = e
+				return m
+			}
+
+			cs = append(cs, c)
+		}
+
+		return bytes.Join(cs, []byte("\n"))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// rebaseBodyFiles rewrites body files referenced by partial from relative to the blueprint, so
+// they still resolve once the partial is inlined
+func rebaseBodyFiles(b []byte, from string) []byte {
+	cs := strings.Split(string(b), "\n")
+
+	for i, c := range cs {
+		rs := bodyFilePattern.FindStringSubmatchIndex(c)
+		if rs == nil {
+			continue
+		}
+
+		cs[i] = c[:rs[4]] + relativeTo(from, strings.TrimSpace(c[rs[4]:rs[5]])) + c[rs[5]:]
+	}
+
+	return []byte(strings.Join(cs, "\n"))
 }
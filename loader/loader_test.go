@@ -1,7 +1,16 @@
 package loader_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bukalapak/snowboard/loader"
 	"github.com/stretchr/testify/assert"
@@ -28,3 +37,309 @@ func TestRead_helperFuncs(t *testing.T) {
 	assert.Contains(t, string(b), `"type": "object",`)
 	assert.Contains(t, string(b), `            {`) // indented by 12 spaces
 }
+
+func TestLoad_stdin(t *testing.T) {
+	f, err := ioutil.TempFile("", "snowboard")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString("# API\n<!-- include(../fixtures/partials/users.apib) -->\n")
+	f.Seek(0, 0)
+
+	stdin := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = stdin }()
+
+	b, err := loader.Load(loader.Stdin)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "# API")
+	assert.Contains(t, string(b), "# Group Users")
+	assert.Empty(t, loader.Seeds(loader.Stdin))
+}
+
+func TestLoad_url(t *testing.T) {
+	var auth string
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		http.FileServer(http.Dir("../fixtures")).ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	loader.HTTPHeader.Set("Authorization", "Bearer secret")
+	defer loader.HTTPHeader.Del("Authorization")
+
+	b, err := loader.Load(s.URL + "/seeds/API.apib")
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `seeds usage`)
+	assert.Contains(t, string(b), `user-related`)
+	assert.Equal(t, "Bearer secret", auth)
+
+	assert.Equal(t, []string{"seed.json", "seed-user.json"}, loader.Seeds(s.URL+"/seeds/API.apib"))
+
+	_, err = loader.Load(s.URL + "/missing.apib")
+	assert.EqualError(t, err, s.URL+"/missing.apib: 404 Not Found")
+}
+
+func TestLoad_faker(t *testing.T) {
+	f, err := ioutil.TempFile("", "snowboard")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString(`{"id": "{{faker.uuid}}", "at": "{{faker.now}}"}`)
+	f.Close()
+
+	b, err := loader.Load(f.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id": "{{faker.uuid}}", "at": "{{faker.now}}"}`, string(b))
+}
+
+func TestLoad_fakerUnknown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, s := range map[string]string{
+		"top.apib":           `{"id": "{{faker.uid}}"}`,
+		"main.apib":          `{{partial "partial.apib"}}`,
+		"partial.apib":       `{"id": "{{faker.uuid}}", "city": "{{ faker.city }}"}`,
+		"known.apib":         `{{partial "known-partial.apib"}}`,
+		"known-partial.apib": `{"id": "{{faker.uuid}}"}`,
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(s), 0644))
+	}
+
+	b, err := loader.Load(filepath.Join(dir, "top.apib"))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id": "{{faker.uid}}"}`, string(b))
+
+	b, err = loader.Load(filepath.Join(dir, "main.apib"))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id": "{{faker.uuid}}", "city": "{{faker.city}}"}`, string(b))
+
+	b, err = loader.Load(filepath.Join(dir, "known.apib"))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id": "{{faker.uuid}}"}`, string(b))
+}
+
+func TestLoad_gzip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	fs, err := ioutil.ReadDir("../fixtures/seeds")
+	assert.Nil(t, err)
+
+	for _, f := range fs {
+		name := f.Name()
+
+		b, err := ioutil.ReadFile(filepath.Join("../fixtures/seeds", name))
+		assert.Nil(t, err)
+
+		if name == "API.apib" {
+			var bf bytes.Buffer
+
+			z := gzip.NewWriter(&bf)
+			z.Write(b)
+			z.Close()
+
+			name, b = "API.apib.gz", bf.Bytes()
+		}
+
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), b, 0644))
+	}
+
+	b, err := loader.Load(filepath.Join(dir, "API.apib.gz"))
+	assert.Nil(t, err)
+
+	c, err := loader.Load("../fixtures/seeds/API.apib")
+	assert.Nil(t, err)
+	assert.Equal(t, string(c), string(b))
+}
+
+func TestLoad_includeGlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "partials"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "API.apib"), []byte("# API\n<!-- include(partials/*.apib) -->\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "partials", "users.apib"), []byte("# Group Users\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "partials", "messages.apib"), []byte("# Group Messages\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "partials", "notes.txt"), []byte("# Group Notes\n"), 0644))
+
+	b, err := loader.Load(filepath.Join(dir, "API.apib"))
+	assert.Nil(t, err)
+	assert.Equal(t, "# API\n# Group Messages\n\n# Group Users\n", string(b))
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "partials", "messages.apib"),
+		filepath.Join(dir, "partials", "users.apib"),
+	}, loader.Includes(filepath.Join(dir, "API.apib")))
+}
+
+func TestBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "API.apib"), []byte("<!-- seed(seed.json) -->\n# API\n<!-- include(users.apib) -->\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "users.apib"), []byte("# Group Users\n{{partial \"user.apib\"}}\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "user.apib"), []byte("## User [/users/{{.id}}]"), 0644))
+
+	b, err := loader.Bundle(filepath.Join(dir, "API.apib"))
+	assert.Nil(t, err)
+	assert.Equal(t, "<!-- seed(seed.json) -->\n# API\n# Group Users\n## User [/users/{{.id}}]\n\n", string(b))
+}
+
+func TestBundle_circular(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "API.apib"), []byte("# API\n<!-- include(a.apib) -->\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "a.apib"), []byte("<!-- include(b.apib) -->\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "b.apib"), []byte("<!-- partial(API.apib) -->\n"), 0644))
+
+	_, err = loader.Bundle(filepath.Join(dir, "API.apib"))
+	assert.EqualError(t, err, "circular include: API.apib -> a.apib -> b.apib -> API.apib")
+}
+
+func TestLoad_bodyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "examples"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "examples", "user.json"), []byte("{\n  \"id\": 1\n}\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "API.apib"), []byte("## User [/users/1]\n### Get [GET]\n+ Response 200 (application/json)\n    + Body (file: examples/user.json)\n"), 0644))
+
+	b, err := loader.Load(filepath.Join(dir, "API.apib"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "    + Body\n\n            {\n              \"id\": 1\n            }\n")
+	assert.Equal(t, []string{"examples/user.json"}, loader.Seeds(filepath.Join(dir, "API.apib")))
+
+	assert.Nil(t, os.Remove(filepath.Join(dir, "examples", "user.json")))
+
+	_, err = loader.Load(filepath.Join(dir, "API.apib"))
+	assert.NotNil(t, err)
+}
+
+func TestLoad_bodyFilePartial(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "users", "examples"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "users", "examples", "user.json"), []byte("{\n  \"id\": 1\n}\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "users", "users.apib"), []byte("## User [/users/1]\n### Get [GET]\n+ Response 200 (application/json)\n    + Body (file: examples/user.json)\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "API.apib"), []byte("# API\n<!-- include(users/users.apib) -->\n"), 0644))
+
+	b, err := loader.Load(filepath.Join(dir, "API.apib"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "    + Body\n\n            {\n              \"id\": 1\n            }\n")
+	assert.Equal(t, []string{"users/examples/user.json"}, loader.Seeds(filepath.Join(dir, "API.apib")))
+
+	b, err = loader.Bundle(filepath.Join(dir, "API.apib"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "    + Body (file: users/examples/user.json)\n")
+
+	assert.Nil(t, os.Remove(filepath.Join(dir, "users", "examples", "user.json")))
+
+	_, err = loader.Load(filepath.Join(dir, "API.apib"))
+	assert.NotNil(t, err)
+}
+
+func TestLoad_remoteInclude(t *testing.T) {
+	hits := 0
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.apib" {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		hits++
+		w.Write([]byte("## Errors"))
+	}))
+	defer s.Close()
+
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "API.apib")
+	src := "# API\n<!-- include(" + s.URL + "/errors.apib?v=1) -->\n<!-- include(local.apib) -->\n"
+
+	assert.Nil(t, ioutil.WriteFile(name, []byte(src), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "local.apib"), []byte("## Local"), 0644))
+
+	for i := 0; i < 2; i++ {
+		b, err := loader.Load(name)
+		assert.Nil(t, err)
+		assert.Contains(t, string(b), "## Errors")
+		assert.Contains(t, string(b), "## Local")
+	}
+
+	assert.Equal(t, 1, hits)
+
+	b, err := loader.Bundle(name)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "## Errors")
+
+	assert.Equal(t, []string{filepath.Join(dir, "local.apib")}, loader.Includes(name))
+	assert.Equal(t, []string{s.URL + "/errors.apib?v=1"}, loader.RemoteIncludes(name))
+
+	defer func(d time.Duration) { loader.RemoteTimeout = d }(loader.RemoteTimeout)
+	loader.RemoteTimeout = 50 * time.Millisecond
+
+	assert.Nil(t, ioutil.WriteFile(name, []byte(strings.Replace(src, "errors.apib?v=1", "slow.apib", 1)), 0644))
+
+	_, err = loader.Bundle(name)
+	assert.NotNil(t, err)
+
+	defer func(d time.Duration) { loader.RemoteCacheTTL = d }(loader.RemoteCacheTTL)
+	loader.RemoteCacheTTL = 0
+
+	assert.Nil(t, ioutil.WriteFile(name, []byte(src), 0644))
+	s.Close()
+
+	b, err = loader.Bundle(name)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "## Errors")
+}
+
+func TestLoad_remoteIncludeHeaders(t *testing.T) {
+	loader.HTTPHeader.Set("Authorization", "Bearer secret")
+	defer loader.HTTPHeader.Del("Authorization")
+
+	var third string
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		third = r.Header.Get("Authorization")
+		w.Write([]byte("## Third"))
+	}))
+	defer other.Close()
+
+	var own []string
+
+	var s *httptest.Server
+	s = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		own = append(own, r.Header.Get("Authorization"))
+
+		if r.URL.Path == "/API.apib" {
+			w.Write([]byte("# API\n<!-- include(" + other.URL + "/third.apib) -->\n<!-- include(" + s.URL + "/own.apib) -->\n"))
+			return
+		}
+
+		w.Write([]byte("## Own"))
+	}))
+	defer s.Close()
+
+	b, err := loader.Load(s.URL + "/API.apib")
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "## Third")
+	assert.Contains(t, string(b), "## Own")
+
+	assert.Equal(t, "", third)
+	assert.Equal(t, []string{"Bearer secret", "Bearer secret"}, own)
+}
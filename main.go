@@ -4,22 +4,45 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/big"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"text/tabwriter"
 	"time"
 
 	"github.com/bukalapak/snowboard/adapter/drafter"
 	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/coverage"
+	"github.com/bukalapak/snowboard/diff"
 	"github.com/bukalapak/snowboard/loader"
 	"github.com/bukalapak/snowboard/mock"
 	snowboard "github.com/bukalapak/snowboard/parser"
 	"github.com/bukalapak/snowboard/render"
+	"github.com/bukalapak/snowboard/stats"
 	xerrors "github.com/pkg/errors"
 	"github.com/rs/cors"
 	cli "gopkg.in/urfave/cli.v1"
@@ -43,8 +66,54 @@ func main() {
 	app.Name = "snowboard"
 	app.Usage = "API blueprint toolkit"
 	app.Version = versionStr
+	app.ErrWriter = os.Stderr
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "engine",
+			Value: snowboard.DefaultEngine,
+			Usage: "API blueprint parser engine: " + strings.Join(snowboard.Engines(), ", "),
+		},
+		cli.StringSliceFlag{
+			Name:  "header",
+			Usage: "HTTP header sent when input is a URL, e.g. \"Authorization: Bearer token\"",
+		},
+		cli.BoolFlag{
+			Name:  "no-color",
+			Usage: "Disable colored output",
+		},
+		cli.DurationFlag{
+			Name:  "http-timeout",
+			Value: loader.HTTPClient.Timeout,
+			Usage: "Timeout of fetching input given as URL",
+		},
+		cli.StringFlag{
+			Name:  "config",
+			Usage: "Configuration file, defaults to " + defaultConfig + " when present",
+		},
+	}
 	app.Before = func(c *cli.Context) error {
-		if c.Args().Present() && c.Args().Get(1) == "" {
+		noColor = c.Bool("no-color")
+
+		if err := loadConfig(c.String("config")); err != nil {
+			return exitError(err.Error())
+		}
+
+		if err := snowboard.Use(c.String("engine")); err != nil {
+			return exitError(err.Error())
+		}
+
+		for _, h := range c.StringSlice("header") {
+			z := strings.SplitN(h, ":", 2)
+			if len(z) != 2 {
+				return exitError(fmt.Sprintf("Invalid header %q, use \"Key: Value\"", h))
+			}
+
+			loader.HTTPHeader.Add(strings.TrimSpace(z[0]), strings.TrimSpace(z[1]))
+		}
+
+		loader.HTTPClient.Timeout = c.Duration("http-timeout")
+
+		if c.Args().Present() && c.Args().Get(1) == "" && cfg.Input == "" {
 			cli.ShowCommandHelp(c, c.Args().Get(0))
 		}
 
@@ -52,49 +121,260 @@ func main() {
 	}
 	app.Commands = []cli.Command{
 		{
-			Name:  "lint",
-			Usage: "Validate API blueprint",
+			Name:      "lint",
+			Usage:     "Validate API blueprint",
+			ArgsUsage: "FILE...",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "format",
+					Value: "table",
+					Usage: "Output format: table or json",
+				},
+				cli.BoolFlag{
+					Name:  "fail-on-warnings",
+					Usage: "Exit with non-zero status on warnings",
+				},
+				cli.BoolFlag{
+					Name:  "check-examples",
+					Usage: "Validate JSON response examples against their schema",
+				},
+				cli.StringSliceFlag{
+					Name:  "allow-status",
+					Usage: "Custom response status code allowed, repeatable or comma separated",
+				},
+			},
 			Action: func(c *cli.Context) error {
-				if c.Args().Get(0) == "" {
+				if inputArg(c) == "" {
 					return nil
 				}
 
-				if err := validate(c, c.Args().Get(0)); err != nil {
-					if strings.Contains(err.Error(), "read failed") {
-						return xerrors.Cause(err)
-					}
+				if err := validate(c, inputArgs(c)); err != nil {
+					return commandError(err)
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:      "diff",
+			Usage:     "Compare two API blueprints",
+			ArgsUsage: "OLD NEW",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "format",
+					Value: "table",
+					Usage: "Output format: table or json",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 2 {
+					return exitError("diff requires two API blueprints")
+				}
 
-					return cli.NewExitError(err.Error(), 1)
+				if err := diffAPI(c, c.Args().Get(0), c.Args().Get(1)); err != nil {
+					return exitError(err.Error())
 				}
 
 				return nil
 			},
 		},
 		{
-			Name:  "html",
-			Usage: "Render HTML documentation",
+			Name:      "changelog",
+			Usage:     "Render Markdown changelog between two API blueprints",
+			ArgsUsage: "OLD NEW",
 			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "title",
+					Value: "Changelog",
+					Usage: "Title of the changelog, e.g. the released version",
+				},
 				cli.StringFlag{
 					Name:  "o",
-					Usage: "HTML file",
+					Usage: "Markdown file, defaults to standard output",
+				},
+				cli.BoolFlag{
+					Name:  "q",
+					Usage: "Quiet mode",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 2 {
+					return exitError("changelog requires two API blueprints")
+				}
+
+				if err := renderChangelog(c, c.Args().Get(0), c.Args().Get(1), c.String("o")); err != nil {
+					return exitError(err.Error())
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:      "conform",
+			Usage:     "Validate JSON response against API blueprint",
+			ArgsUsage: "BLUEPRINT METHOD PATH FILE",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "status",
+					Usage: "Status code of the response, defaults to the first successful one",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 4 {
+					return exitError("conform requires API blueprint, method, path and JSON file")
+				}
+
+				a := c.Args()
+
+				if err := conformResponse(c, a.Get(0), a.Get(1), a.Get(2), a.Get(3), c.Int("status")); err != nil {
+					return exitError(err.Error())
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:      "coverage",
+			Usage:     "Report API blueprint endpoints exercised by access log",
+			ArgsUsage: "BLUEPRINT LOG",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "format",
+					Value: "table",
+					Usage: "Output format: table or json",
+				},
+				cli.StringFlag{
+					Name:  "base-path",
+					Usage: "Prefix of every route in the log, e.g. /api/v2",
 				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 2 {
+					return exitError("coverage requires API blueprint and access log")
+				}
+
+				if err := reportCoverage(c, c.Args().Get(0), c.Args().Get(1)); err != nil {
+					return exitError(err.Error())
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:      "query",
+			Usage:     "Print API element json subtree",
+			ArgsUsage: "BLUEPRINT SELECTOR",
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 2 {
+					return exitError("query requires API blueprint and selector")
+				}
+
+				if err := queryElement(c, c.Args().Get(0), c.Args().Get(1)); err != nil {
+					return exitError(err.Error())
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:  "stats",
+			Usage: "Summarize API blueprint",
+			Flags: []cli.Flag{
 				cli.StringFlag{
+					Name:  "format",
+					Value: "table",
+					Usage: "Output format: table or json",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if inputArg(c) == "" {
+					return nil
+				}
+
+				if err := summarize(c, inputArg(c)); err != nil {
+					return exitError(err.Error())
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:  "html",
+			Usage: "Render HTML documentation",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "validate",
+					Usage: "Validate blueprint first, aborting with its errors instead of rendering",
+				},
+				cli.StringSliceFlag{
+					Name:  "o",
+					Usage: "HTML file, repeat it along with -t to render several variants",
+				},
+				cli.StringSliceFlag{
 					Name:  "t",
-					Value: "alpha",
-					Usage: "Template for HTML documentation",
+					Usage: "Template for HTML documentation (default: \"alpha\")",
 				},
 				cli.BoolFlag{
 					Name:  "q",
 					Usage: "Quiet mode",
 				},
+				cli.BoolFlag{
+					Name:  "split",
+					Usage: "Render a page for every resource group into directory given by -o",
+				},
+				cli.BoolFlag{
+					Name:  "search",
+					Usage: "Generate search-index.json alongside HTML output",
+				},
+				cli.StringSliceFlag{
+					Name:  "only",
+					Usage: "Render only resource groups of given title, repeatable",
+				},
+				cli.StringSliceFlag{
+					Name:  "exclude",
+					Usage: "Skip resource groups of given title, repeatable",
+				},
+				cli.BoolFlag{
+					Name:  "recursive",
+					Usage: "Render every *.apib under input directory into -o directory",
+				},
+				cli.IntFlag{
+					Name:  "jobs",
+					Value: runtime.GOMAXPROCS(0),
+					Usage: "Number of blueprints rendered concurrently with --recursive",
+				},
+				cli.StringFlag{
+					Name:  "template-dir",
+					Usage: "Directory of partial templates, usable by name like {{template \"header\" .}}",
+				},
+				cli.StringFlag{
+					Name:  "host",
+					Usage: "Override HOST of the blueprint in example URLs and snippets",
+				},
+				cli.BoolFlag{
+					Name:  "diagrams",
+					Usage: "Draw transitions diagram of every resource",
+				},
 			},
 			Action: func(c *cli.Context) error {
-				if c.Args().Get(0) == "" {
+				if inputArg(c) == "" {
+					return nil
+				}
+
+				if c.Bool("recursive") {
+					if err := renderHTMLTree(c, inputArg(c), firstFlag(c, "o", ""), firstFlag(c, "t", "alpha")); err != nil {
+						return commandError(err)
+					}
+
 					return nil
 				}
 
-				if err := renderHTML(c, c.Args().Get(0), c.String("o"), c.String("t")); err != nil {
-					return cli.NewExitError(err.Error(), 1)
+				if err := preflight(c, []string{inputArg(c)}); err != nil {
+					return commandError(err)
+				}
+
+				if err := renderHTML(c, inputArg(c), c.StringSlice("o"), c.StringSlice("t")); err != nil {
+					return commandError(err)
 				}
 
 				return nil
@@ -114,18 +394,79 @@ func main() {
 					Value: ":8088",
 					Usage: "HTTP server listen address",
 				},
+				cli.StringFlag{
+					Name:  "tls-cert",
+					Usage: "TLS certificate file, requires --tls-key",
+				},
+				cli.StringFlag{
+					Name:  "tls-key",
+					Usage: "TLS private key file, requires --tls-cert",
+				},
+				cli.BoolFlag{
+					Name:  "self-signed",
+					Usage: "Serve HTTPS using ephemeral self-signed certificate",
+				},
+				cli.StringFlag{
+					Name:  "template-dir",
+					Usage: "Directory of partial templates, usable by name like {{template \"header\" .}}",
+				},
+				cli.StringFlag{
+					Name:  "host",
+					Usage: "Override HOST of the blueprint in example URLs and snippets",
+				},
+				cli.BoolFlag{
+					Name:  "live-reload",
+					Usage: "Regenerate documentation on changes and reload browsers",
+				},
+				cli.DurationFlag{
+					Name:  "debounce",
+					Value: defaultDebounce,
+					Usage: "With --live-reload, wait until changes settle for duration before regenerating",
+				},
+				cli.BoolFlag{
+					Name:  "live",
+					Usage: "Render documentation on request instead of writing index.html, re-parsing on changes",
+				},
+				cli.BoolFlag{
+					Name:  "open",
+					Usage: "Open documentation in the default browser once the server is up",
+				},
 			},
 			Action: func(c *cli.Context) error {
-				if c.Args().Get(0) == "" {
+				if inputArg(c) == "" {
+					return nil
+				}
+
+				input, tplFile := inputArg(c), c.String("t")
+
+				var lr *liveReload
+
+				if c.Bool("live") {
+					if c.Bool("live-reload") {
+						lr = newLiveReload()
+						go watchHTML(c, input, tplFile, lr, func() error { return nil })
+					}
+
+					if err := serveLiveHTML(c, bindAddr(c, "SNOWBOARD_HTML_ADDR"), input, tplFile, lr); err != nil {
+						return exitError(err.Error())
+					}
+
 					return nil
 				}
 
-				if err := renderHTML(c, c.Args().Get(0), "index.html", c.String("t")); err != nil {
-					return cli.NewExitError(err.Error(), 1)
+				if err := renderHTML(c, input, []string{"index.html"}, []string{tplFile}); err != nil {
+					return exitError(err.Error())
+				}
+
+				if c.Bool("live-reload") {
+					lr = newLiveReload()
+					go watchHTML(c, input, tplFile, lr, func() error {
+						return actionCommand(c, input, "index.html", tplFile)
+					})
 				}
 
-				if err := serveHTML(c, c.String("b"), "index.html"); err != nil {
-					return cli.NewExitError(err.Error(), 1)
+				if err := serveHTML(c, bindAddr(c, "SNOWBOARD_HTML_ADDR"), "index.html", lr); err != nil {
+					return exitError(err.Error())
 				}
 
 				return nil
@@ -134,6 +475,51 @@ func main() {
 		{
 			Name:  "apib",
 			Usage: "Render API blueprint",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "validate",
+					Usage: "Validate blueprint first, aborting with its errors instead of rendering",
+				},
+				cli.StringFlag{
+					Name:  "o",
+					Usage: "API blueprint output file",
+				},
+				cli.BoolFlag{
+					Name:  "q",
+					Usage: "Quiet mode",
+				},
+				cli.StringSliceFlag{
+					Name:  "only",
+					Usage: "Render only resource groups of given title, repeatable",
+				},
+				cli.StringSliceFlag{
+					Name:  "exclude",
+					Usage: "Skip resource groups of given title, repeatable",
+				},
+				cli.BoolFlag{
+					Name:  "checksum",
+					Usage: "Write SHA-256 of output to <output>.sha256, leaving unchanged output untouched",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if inputArg(c) == "" {
+					return nil
+				}
+
+				if err := preflight(c, []string{inputArg(c)}); err != nil {
+					return commandError(err)
+				}
+
+				if err := renderAPIB(c, inputArg(c), c.String("o")); err != nil {
+					return commandError(err)
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:  "bundle",
+			Usage: "Bundle API blueprint with includes inlined",
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:  "o",
@@ -145,12 +531,12 @@ func main() {
 				},
 			},
 			Action: func(c *cli.Context) error {
-				if c.Args().Get(0) == "" {
+				if inputArg(c) == "" {
 					return nil
 				}
 
-				if err := renderAPIB(c, c.Args().Get(0), c.String("o")); err != nil {
-					return cli.NewExitError(err.Error(), 1)
+				if err := renderBundle(c, inputArg(c), c.String("o")); err != nil {
+					return exitError(err.Error())
 				}
 
 				return nil
@@ -160,6 +546,10 @@ func main() {
 			Name:  "json",
 			Usage: "Render API element json",
 			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "validate",
+					Usage: "Validate blueprint first, aborting with its errors instead of rendering",
+				},
 				cli.StringFlag{
 					Name:  "o",
 					Usage: "API element output file",
@@ -168,64 +558,350 @@ func main() {
 					Name:  "q",
 					Usage: "Quiet mode",
 				},
+				cli.BoolFlag{
+					Name:  "pretty",
+					Usage: "Indent output",
+				},
+				cli.BoolFlag{
+					Name:  "canonical",
+					Usage: "Sort object keys recursively for byte-stable output",
+				},
+				cli.StringSliceFlag{
+					Name:  "only",
+					Usage: "Render only resource groups of given title, repeatable",
+				},
+				cli.StringSliceFlag{
+					Name:  "exclude",
+					Usage: "Skip resource groups of given title, repeatable",
+				},
+				cli.BoolFlag{
+					Name:  "checksum",
+					Usage: "Write SHA-256 of output to <output>.sha256, leaving unchanged output untouched",
+				},
 			},
 			Action: func(c *cli.Context) error {
-				if c.Args().Get(0) == "" {
+				if inputArg(c) == "" {
 					return nil
 				}
 
-				if err := renderJSON(c, c.Args().Get(0), c.String("o")); err != nil {
-					return cli.NewExitError(err.Error(), 1)
+				if err := preflight(c, []string{inputArg(c)}); err != nil {
+					return commandError(err)
+				}
+
+				if err := renderJSON(c, inputArg(c), c.String("o")); err != nil {
+					return commandError(err)
 				}
 
 				return nil
 			},
 		},
 		{
-			Name:  "list",
-			Usage: "List available routes",
+			Name:  "openapi",
+			Usage: "Render OpenAPI 3.0 document",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "o",
+					Usage: "OpenAPI output file",
+				},
+				cli.BoolFlag{
+					Name:  "q",
+					Usage: "Quiet mode",
+				},
+				cli.StringFlag{
+					Name:  "host",
+					Usage: "Override HOST of the blueprint in example URLs and snippets",
+				},
+			},
 			Action: func(c *cli.Context) error {
-				if c.Args().Get(0) == "" {
+				if inputArg(c) == "" {
 					return nil
 				}
-				if err := outputPath(c, c.Args()); err != nil {
-					return cli.NewExitError(err.Error(), 1)
+
+				if err := renderOpenAPI(c, inputArg(c), c.String("o")); err != nil {
+					return exitError(err.Error())
 				}
+
 				return nil
 			},
 		},
 		{
-			Name:  "mock",
-			Usage: "Run Mock server",
+			Name:    "markdown",
+			Aliases: []string{"md"},
+			Usage:   "Render Markdown documentation",
 			Flags: []cli.Flag{
 				cli.StringFlag{
-					Name:  "b",
-					Value: ":8087",
-					Usage: "HTTP server listen address",
+					Name:  "o",
+					Usage: "Markdown output file",
+				},
+				cli.BoolFlag{
+					Name:  "q",
+					Usage: "Quiet mode",
+				},
+				cli.StringFlag{
+					Name:  "host",
+					Usage: "Override HOST of the blueprint in example URLs and snippets",
 				},
 			},
 			Action: func(c *cli.Context) error {
-				if c.Args().Get(0) == "" {
+				if inputArg(c) == "" {
 					return nil
 				}
 
-				if err := serveMock(c, c.String("b"), c.Args()); err != nil {
-					return cli.NewExitError(err.Error(), 1)
+				if err := renderMarkdown(c, inputArg(c), c.String("o")); err != nil {
+					return exitError(err.Error())
 				}
 
 				return nil
 			},
 		},
-	}
-
-	app.Run(os.Args)
-}
+		{
+			Name:  "postman",
+			Usage: "Render Postman collection",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "o",
+					Usage: "Postman collection output file",
+				},
+				cli.BoolFlag{
+					Name:  "q",
+					Usage: "Quiet mode",
+				},
+				cli.StringFlag{
+					Name:  "host",
+					Usage: "Override HOST of the blueprint in example URLs and snippets",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if inputArg(c) == "" {
+					return nil
+				}
 
-func readFile(fn string) ([]byte, error) {
-	info, err := os.Stat(fn)
-	if err != nil {
-		return nil, errors.New("File is not exist")
-	}
+				if err := renderPostman(c, inputArg(c), c.String("o")); err != nil {
+					return exitError(err.Error())
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:  "list",
+			Usage: "List available routes",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "jobs",
+					Value: runtime.GOMAXPROCS(0),
+					Usage: "Number of blueprints loaded concurrently",
+				},
+				cli.StringFlag{
+					Name:  "sort",
+					Value: "path",
+					Usage: "Sort routes by: path, method or status",
+				},
+				cli.StringFlag{
+					Name:  "format",
+					Value: "table",
+					Usage: "Output format: table or json",
+				},
+				cli.BoolFlag{
+					Name:  "fail-on-duplicate",
+					Usage: "Exit with non-zero status when blueprints define the same route",
+				},
+				cli.StringFlag{
+					Name:  "base-path",
+					Usage: "Prefix of every route, e.g. /api/v2",
+				},
+				cli.BoolFlag{
+					Name:  "grouped",
+					Usage: "Group routes under their resource group titles",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if inputArg(c) == "" {
+					return nil
+				}
+				if err := outputPath(c, inputArgs(c)); err != nil {
+					return exitError(err.Error())
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "mock",
+			Usage: "Run Mock server",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "validate",
+					Usage: "Validate blueprint first, aborting with its errors instead of serving",
+				},
+				cli.StringFlag{
+					Name:  "b",
+					Value: ":8087",
+					Usage: "HTTP server listen address",
+				},
+				cli.StringFlag{
+					Name:  "tls-cert",
+					Usage: "TLS certificate file, requires --tls-key",
+				},
+				cli.StringFlag{
+					Name:  "tls-key",
+					Usage: "TLS private key file, requires --tls-cert",
+				},
+				cli.BoolFlag{
+					Name:  "self-signed",
+					Usage: "Serve HTTPS using ephemeral self-signed certificate",
+				},
+				cli.IntFlag{
+					Name:  "jobs",
+					Value: runtime.GOMAXPROCS(0),
+					Usage: "Number of blueprints loaded concurrently",
+				},
+				cli.StringSliceFlag{
+					Name:  "cors-origin",
+					Usage: "Allowed CORS origin, can be repeated or comma separated (default: *)",
+				},
+				cli.StringSliceFlag{
+					Name:  "cors-methods",
+					Usage: "Allowed CORS methods, can be repeated or comma separated",
+				},
+				cli.StringSliceFlag{
+					Name:  "cors-headers",
+					Usage: "Allowed CORS request headers, can be repeated or comma separated (default: *)",
+				},
+				cli.BoolFlag{
+					Name:  "strict-request",
+					Usage: "Validate request body against request schema",
+				},
+				cli.DurationFlag{
+					Name:  "delay",
+					Usage: "Delay every response, e.g. 250ms",
+				},
+				cli.StringFlag{
+					Name:  "param-placeholder",
+					Value: mock.DefaultParamPlaceholder,
+					Usage: "Format of path parameter placeholder in response body",
+				},
+				cli.DurationFlag{
+					Name:  "reload-interval",
+					Usage: "Poll blueprints and seeds at interval, reloading routes on change, e.g. 1s",
+				},
+				cli.DurationFlag{
+					Name:  "debounce",
+					Value: defaultDebounce,
+					Usage: "With --reload-interval, wait until changes settle for duration before reloading",
+				},
+				cli.BoolFlag{
+					Name:  "dynamic",
+					Usage: "Expand {{faker.<name>}} directives in response body on every request",
+				},
+				cli.BoolFlag{
+					Name:  "indent-xml",
+					Usage: "Indent XML response bodies",
+				},
+				cli.StringSliceFlag{
+					Name:  "media-type",
+					Usage: "Handle custom media type as a base type, e.g. application/vnd.company.v2=application/json",
+				},
+				cli.BoolFlag{
+					Name:  "stateful",
+					Usage: "Keep resources created by POST requests in memory, served back to GET and DELETE by id",
+				},
+				cli.StringFlag{
+					Name:  "rate-limit",
+					Usage: "Respond 429 to clients sending more requests than allowed per duration, e.g. 10/1m",
+				},
+				cli.StringFlag{
+					Name:  "basic-auth",
+					Usage: "Respond 401 to requests without HTTP basic credentials, e.g. user:pass",
+				},
+				cli.BoolFlag{
+					Name:  "cycle-examples",
+					Usage: "Rotate through examples sharing the same status code on successive requests",
+				},
+				cli.BoolFlag{
+					Name:  "fail-on-duplicate",
+					Usage: "Exit with non-zero status when blueprints define the same route",
+				},
+				cli.StringFlag{
+					Name:  "base-path",
+					Usage: "Prefix of every route, e.g. /api/v2",
+				},
+				cli.IntFlag{
+					Name:  "gzip-min-length",
+					Value: mock.DefaultGzipMinLength,
+					Usage: "Minimum response body length compressed for clients accepting gzip",
+				},
+				cli.Int64Flag{
+					Name:  "max-body-size",
+					Value: mock.DefaultMaxBodySize,
+					Usage: "Maximum request body size in bytes, larger requests are responded 413",
+				},
+				cli.StringFlag{
+					Name:  "not-found-body",
+					Usage: "JSON body of 404 response for unmatched routes, defaults to closest routes suggestion",
+				},
+				cli.StringFlag{
+					Name:  "admin-prefix",
+					Value: "/__",
+					Usage: "Prefix of health and routes endpoints of mock server itself, empty disables them",
+				},
+				cli.StringFlag{
+					Name:  "proxy",
+					Usage: "Forward requests without blueprint example to upstream URL",
+				},
+				cli.StringFlag{
+					Name:  "cassette",
+					Usage: "Record responses captured by --proxy into cassette file",
+				},
+				cli.BoolFlag{
+					Name:  "replay",
+					Usage: "Serve responses recorded in --cassette before the blueprint",
+				},
+				cli.DurationFlag{
+					Name:  "upstream-timeout",
+					Usage: "Respond 504 when --proxy upstream takes longer, e.g. 5s",
+				},
+				cli.BoolFlag{
+					Name:  "upstream-fallback",
+					Usage: "Send every request to --proxy upstream first, serving blueprint examples or --cassette responses when it fails or times out",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if inputArg(c) == "" {
+					return nil
+				}
+
+				if err := preflight(c, inputArgs(c)); err != nil {
+					return commandError(err)
+				}
+
+				if err := serveMock(c, bindAddr(c, "SNOWBOARD_MOCK_ADDR"), inputArgs(c)); err != nil {
+					return exitError(err.Error())
+				}
+
+				return nil
+			},
+		},
+	}
+
+	for i := range app.Commands {
+		app.Commands[i].Before = func(c *cli.Context) error {
+			if err := applyConfig(c); err != nil {
+				return err
+			}
+
+			setupProgress(c)
+			return nil
+		}
+	}
+
+	app.Run(os.Args)
+}
+
+func readFile(fn string) ([]byte, error) {
+	info, err := os.Stat(fn)
+	if err != nil {
+		return nil, errors.New("File is not exist")
+	}
 
 	if info.IsDir() {
 		return nil, errors.New("File is a directory")
@@ -234,14 +910,27 @@ func readFile(fn string) ([]byte, error) {
 	return ioutil.ReadFile(fn)
 }
 
-func readTemplate(fn string) ([]byte, error) {
-	tf, err := readFile(fn)
-	if err == nil {
-		return tf, nil
+// builtinTemplates holds templates embedded in FS by name, read and parsed once at init so
+// rendering them does not parse them again
+var builtinTemplates = map[string][]byte{}
+
+func init() {
+	for _, name := range []string{"alpha", "beta"} {
+		tf, err := readBuiltinTemplate(name)
+		if err != nil {
+			continue
+		}
+
+		builtinTemplates[name] = tf
+
+		render.Precompile(string(tf))
+		diagramOptions.Precompile(string(tf))
 	}
+}
 
+func readBuiltinTemplate(name string) ([]byte, error) {
 	fs := FS(false)
-	ff, err := fs.Open("/templates/" + fn + ".html")
+	ff, err := fs.Open("/templates/" + name + ".html")
 	if err != nil {
 		return nil, err
 	}
@@ -250,21 +939,114 @@ func readTemplate(fn string) ([]byte, error) {
 	return ioutil.ReadAll(ff)
 }
 
-func renderHTML(c *cli.Context, input, output, tplFile string) error {
-	bp, err := snowboard.Load(input)
+func readTemplate(fn string) ([]byte, error) {
+	tf, err := readFile(fn)
+	if err == nil {
+		return tf, nil
+	}
+
+	if tf, ok := builtinTemplates[fn]; ok {
+		return tf, nil
+	}
+
+	return readBuiltinTemplate(fn)
+}
+
+// renderHTML loads blueprint once and renders it with every template into its output,
+// a single template is used for all outputs.
+func renderHTML(c *cli.Context, input string, outputs, tplFiles []string) error {
+	if len(tplFiles) == 0 {
+		tplFiles = []string{"alpha"}
+	}
+
+	if len(outputs) == 0 {
+		outputs = []string{""}
+	}
+
+	if len(tplFiles) > 1 && len(tplFiles) != len(outputs) {
+		return errors.New("Every template needs its output, pair each -t with -o")
+	}
+
+	bp, err := loadHTML(c, input)
 	if err != nil {
 		return err
 	}
 
+	for i, output := range outputs {
+		tplFile := tplFiles[0]
+		if len(tplFiles) > 1 {
+			tplFile = tplFiles[i]
+		}
+
+		if err = renderHTMLFile(c, bp, output, tplFile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadHTML loads input with host override and group filters applied
+func loadHTML(c *cli.Context, input string) (*api.API, error) {
+	bp, err := snowboard.Load(input)
+	if err != nil {
+		return nil, err
+	}
+
+	overrideHost(c, bp)
+
+	only, exclude := groupFilters(c)
+	bp = api.Filter(bp, only, exclude)
+
+	return bp, nil
+}
+
+// diagramOptions make diagram function of templates render resource diagrams, see --diagrams
+var diagramOptions = render.Options{Funcs: map[string]interface{}{"diagram": render.Diagram}}
+
+// htmlOptions returns render options of HTML flags, diagrams and partials of --template-dir.
+// Partials are read again on every call, so changes to them are rendered.
+func htmlOptions(c *cli.Context) (render.Options, error) {
+	opt := render.Options{}
+	if c.Bool("diagrams") {
+		opt = diagramOptions
+	}
+
+	ps, err := render.ReadPartials(c.String("template-dir"))
+	if err != nil {
+		return opt, err
+	}
+
+	opt.Partials = ps
+
+	return opt, nil
+}
+
+func renderHTMLFile(c *cli.Context, bp *api.API, output, tplFile string) error {
 	tf, err := readTemplate(tplFile)
 	if err != nil {
 		return err
 	}
 
+	opt, err := htmlOptions(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("search") {
+		if err = renderSearchIndex(c, output, bp); err != nil {
+			return err
+		}
+	}
+
+	if c.Bool("split") {
+		return renderHTMLMulti(c, opt, string(tf), output, bp)
+	}
+
 	if output == "" {
 		var bf bytes.Buffer
 
-		if err = render.HTML(string(tf), &bf, bp); err != nil {
+		if err = opt.HTML(string(tf), &bf, bp); err != nil {
 			return err
 		}
 
@@ -278,55 +1060,103 @@ func renderHTML(c *cli.Context, input, output, tplFile string) error {
 	}
 	defer of.Close()
 
-	err = render.HTML(string(tf), of, bp)
+	err = opt.HTML(string(tf), of, bp)
 	if err != nil {
 		return err
 	}
 
 	if !c.Bool("q") {
-		fmt.Fprintf(c.App.Writer, "[%s] %s: HTML has been generated!\n", time.Now().Format(time.RFC3339), of.Name())
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("[%s] %s: HTML has been generated!", time.Now().Format(time.RFC3339), of.Name())))
 	}
 
 	return nil
 }
 
-func renderAPIB(c *cli.Context, input, output string) error {
-	b, err := loader.Load(input)
+func renderSearchIndex(c *cli.Context, output string, bp *api.API) error {
+	if output == "" {
+		return errors.New("Search index requires output, use -o flag")
+	}
+
+	dir := filepath.Dir(output)
+	if c.Bool("split") {
+		dir = output
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	of, err := os.Create(filepath.Join(dir, "search-index.json"))
 	if err != nil {
 		return err
 	}
+	defer of.Close()
+
+	if err = render.SearchIndex(of, bp); err != nil {
+		return err
+	}
 
+	if !c.Bool("q") {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("[%s] %s: Search index has been generated!", time.Now().Format(time.RFC3339), of.Name())))
+	}
+
+	return nil
+}
+
+func renderHTMLMulti(c *cli.Context, opt render.Options, tpl, output string, bp *api.API) error {
 	if output == "" {
-		fmt.Fprintln(c.App.Writer, string(b))
-		return nil
+		return errors.New("Output directory is required, use -o flag")
 	}
 
-	of, err := os.Create(output)
-	if err != nil {
+	if err := os.MkdirAll(output, 0755); err != nil {
 		return err
 	}
-	defer of.Close()
 
-	_, err = io.Copy(of, bytes.NewReader(b))
+	fs, err := opt.HTMLMulti(tpl, output, bp)
 	if err != nil {
 		return err
 	}
 
 	if !c.Bool("q") {
-		fmt.Fprintf(c.App.Writer, "%s: API blueprint has been generated!\n", of.Name())
+		for _, f := range fs {
+			fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("[%s] %s: HTML has been generated!", time.Now().Format(time.RFC3339), f)))
+		}
 	}
 
 	return nil
 }
 
-func renderJSON(c *cli.Context, input, output string) error {
-	b, err := snowboard.LoadAsJSON(input)
+func renderAPIB(c *cli.Context, input, output string) error {
+	b, err := loader.Load(input)
 	if err != nil {
 		return err
 	}
 
+	only, exclude := groupFilters(c)
+	opt := render.APIBOptions{Only: only, Exclude: exclude}
+
+	if c.Bool("checksum") {
+		var buf bytes.Buffer
+
+		if err = render.APIB(&buf, b, opt); err != nil {
+			return err
+		}
+
+		if output == "" {
+			fmt.Fprintln(c.App.Writer, buf.String())
+			fmt.Fprintln(c.App.ErrWriter, checksum(buf.Bytes()))
+			return nil
+		}
+
+		return writeChecksummed(c, output, buf.Bytes(), "API blueprint")
+	}
+
 	if output == "" {
-		fmt.Fprintln(c.App.Writer, string(b))
+		if err = render.APIB(c.App.Writer, b, opt); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(c.App.Writer)
 		return nil
 	}
 
@@ -336,86 +1166,724 @@ func renderJSON(c *cli.Context, input, output string) error {
 	}
 	defer of.Close()
 
-	_, err = io.Copy(of, bytes.NewReader(b))
-	if err != nil {
+	if err = render.APIB(of, b, opt); err != nil {
 		return err
 	}
 
 	if !c.Bool("q") {
-		fmt.Fprintf(c.App.Writer, "%s: API element JSON has been generated!\n", of.Name())
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("%s: API blueprint has been generated!", of.Name())))
 	}
 
 	return nil
 }
 
-func validate(c *cli.Context, input string) error {
-	b, err := loader.Load(input)
-	if err != nil {
-		return xerrors.Wrap(err, "read failed")
-	}
-
-	bf := bytes.NewReader(b)
-
-	out, err := snowboard.Validate(bf)
+// renderBundle writes API blueprint with includes inlined, seeds and template expressions are kept
+func renderBundle(c *cli.Context, input, output string) error {
+	b, err := loader.Bundle(input)
 	if err != nil {
 		return err
 	}
 
-	if out == nil {
-		fmt.Fprintln(c.App.Writer, "OK")
+	if output == "" {
+		fmt.Fprint(c.App.Writer, string(b))
 		return nil
 	}
 
-	var buf bytes.Buffer
-
-	s := "--------"
-	w := tabwriter.NewWriter(&buf, 8, 0, 0, ' ', tabwriter.Debug)
-	fmt.Fprintln(w, "Char Index\tDescription")
-	fmt.Fprintf(w, "%s\t%s\n", s, strings.Repeat(s, 8))
-
-	for _, n := range out.Annotations {
-		for _, m := range n.SourceMaps {
-			fmt.Fprintf(w, "%d:%d\t%s\n", m.Row, m.Col, n.Description)
-		}
+	if err = ioutil.WriteFile(output, b, 0644); err != nil {
+		return err
 	}
 
-	w.Flush()
-
-	if len(out.Annotations) > 0 {
-		return errors.New(buf.String())
+	if !c.Bool("q") {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("%s: API blueprint has been bundled!", output)))
 	}
 
 	return nil
 }
 
-func dash(n int) string {
-	return strings.Repeat("-", n)
+func checksum(b []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(b))
 }
 
-type fsWatcher interface {
-	Add(string) error
-}
+// writeChecksummed writes b to output unless it already holds the same content, so unchanged
+// documentation keeps its modification time. SHA-256 of b is recorded in `<output>.sha256`
+// using sha256sum format, and printed unless quiet.
+func writeChecksummed(c *cli.Context, output string, b []byte, kind string) error {
+	sum := checksum(b)
+	msg := fmt.Sprintf("%s: %s is unchanged", output, kind)
 
-func outputName(c *cli.Context, output string) string {
-	switch c.Command.Name {
-	case "html":
-		if output == "" {
-			return "index.html"
+	if x, err := ioutil.ReadFile(output); err != nil || checksum(x) != sum {
+		if err = ioutil.WriteFile(output, b, 0644); err != nil {
+			return err
 		}
 
-		return output
+		msg = fmt.Sprintf("%s: %s has been generated!", output, kind)
 	}
 
-	return ""
-}
+	side := []byte(fmt.Sprintf("%s  %s\n", sum, filepath.Base(output)))
 
-func actionCommand(c *cli.Context, input, output, tplFile string) error {
-	switch c.Command.Name {
-	case "html":
-		if err := renderHTML(c, input, output, tplFile); err != nil {
+	if x, err := ioutil.ReadFile(output + ".sha256"); err != nil || !bytes.Equal(x, side) {
+		if err = ioutil.WriteFile(output+".sha256", side, 0644); err != nil {
 			return err
 		}
-	case "apib":
+	}
+
+	if !c.Bool("q") {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, msg))
+		fmt.Fprintln(c.App.Writer, sum)
+	}
+
+	return nil
+}
+
+func renderJSON(c *cli.Context, input, output string) error {
+	b, err := snowboard.LoadAsJSON(input)
+	if err != nil {
+		return err
+	}
+
+	only, exclude := groupFilters(c)
+	opt := render.JSONOptions{
+		Pretty:    c.Bool("pretty"),
+		Canonical: c.Bool("canonical"),
+		Only:      only,
+		Exclude:   exclude,
+	}
+
+	if c.Bool("checksum") {
+		var buf bytes.Buffer
+
+		if err = render.JSON(&buf, b, opt); err != nil {
+			return err
+		}
+
+		if output == "" {
+			fmt.Fprintln(c.App.Writer, buf.String())
+			fmt.Fprintln(c.App.ErrWriter, checksum(buf.Bytes()))
+			return nil
+		}
+
+		return writeChecksummed(c, output, buf.Bytes(), "API element JSON")
+	}
+
+	if output == "" {
+		if err = render.JSON(c.App.Writer, b, opt); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(c.App.Writer)
+		return nil
+	}
+
+	of, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer of.Close()
+
+	if err = render.JSON(of, b, opt); err != nil {
+		return err
+	}
+
+	if !c.Bool("q") {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("%s: API element JSON has been generated!", of.Name())))
+	}
+
+	return nil
+}
+
+func renderOpenAPI(c *cli.Context, input, output string) error {
+	bp, err := snowboard.Load(input)
+	if err != nil {
+		return err
+	}
+
+	overrideHost(c, bp)
+
+	if output == "" {
+		return render.OpenAPI(c.App.Writer, bp)
+	}
+
+	of, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer of.Close()
+
+	if err = render.OpenAPI(of, bp); err != nil {
+		return err
+	}
+
+	if !c.Bool("q") {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("%s: OpenAPI document has been generated!", of.Name())))
+	}
+
+	return nil
+}
+
+func renderMarkdown(c *cli.Context, input, output string) error {
+	bp, err := snowboard.Load(input)
+	if err != nil {
+		return err
+	}
+
+	overrideHost(c, bp)
+
+	if output == "" {
+		return render.Markdown(c.App.Writer, bp)
+	}
+
+	of, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer of.Close()
+
+	if err = render.Markdown(of, bp); err != nil {
+		return err
+	}
+
+	if !c.Bool("q") {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("%s: Markdown documentation has been generated!", of.Name())))
+	}
+
+	return nil
+}
+
+func renderPostman(c *cli.Context, input, output string) error {
+	bp, err := snowboard.Load(input)
+	if err != nil {
+		return err
+	}
+
+	overrideHost(c, bp)
+
+	if output == "" {
+		return render.Postman(c.App.Writer, bp)
+	}
+
+	of, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer of.Close()
+
+	if err = render.Postman(of, bp); err != nil {
+		return err
+	}
+
+	if !c.Bool("q") {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("%s: Postman collection has been generated!", of.Name())))
+	}
+
+	return nil
+}
+
+// lintResult holds the annotations of a single linted input.
+type lintResult struct {
+	input string
+	src   []byte
+	out   *api.API
+}
+
+func validate(c *cli.Context, inputs []string) error {
+	rs := make([]lintResult, 0, len(inputs))
+	multi := len(inputs) > 1
+
+	for _, input := range inputs {
+		r, err := lintFile(c, input)
+		if err != nil {
+			if _, ok := err.(*snowboard.ReadError); multi && !ok {
+				err = xerrors.Wrap(err, input)
+			}
+
+			return err
+		}
+
+		rs = append(rs, r)
+	}
+
+	if c.String("format") == "json" {
+		return validateJSON(c, rs, multi)
+	}
+
+	var ns []api.Annotation
+
+	for _, r := range rs {
+		if r.out != nil {
+			ns = append(ns, r.out.Annotations...)
+		}
+	}
+
+	if len(ns) == 0 {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, "OK"))
+		return nil
+	}
+
+	table := annotationTable(rs, multi)
+
+	if lintFailed(c, ns) {
+		return invalidError{errors.New(table)}
+	}
+
+	fmt.Fprint(c.App.Writer, table)
+	return nil
+}
+
+// annotationTable lists annotations of rs with their position, prefixed by file when multi
+func annotationTable(rs []lintResult, multi bool) string {
+	var buf bytes.Buffer
+
+	s := "--------"
+	w := tabwriter.NewWriter(&buf, 8, 0, 0, ' ', tabwriter.Debug)
+
+	if multi {
+		fmt.Fprintln(w, "File\tLine:Col\tSeverity\tDescription")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s, s, s, strings.Repeat(s, 8))
+	} else {
+		fmt.Fprintln(w, "Line:Col\tSeverity\tDescription")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s, s, strings.Repeat(s, 8))
+	}
+
+	for _, r := range rs {
+		if r.out == nil {
+			continue
+		}
+
+		for _, n := range r.out.Annotations {
+			for _, m := range n.SourceMaps {
+				line, col := snowboard.Position(r.src, m.Row)
+
+				if multi {
+					fmt.Fprintf(w, "%s\t", r.input)
+				}
+
+				fmt.Fprintf(w, "%d:%d\t%s\t%s\n", line, col, n.Severity(), n.Description)
+			}
+		}
+	}
+
+	w.Flush()
+
+	return buf.String()
+}
+
+// preflight validates inputs when --validate is given, failing with the table of their errors
+// before anything is rendered or served. Standard input is left for the command to read.
+func preflight(c *cli.Context, inputs []string) error {
+	if !c.Bool("validate") {
+		return nil
+	}
+
+	rs := make([]lintResult, 0, len(inputs))
+	failed := false
+
+	for _, input := range inputs {
+		if input == loader.Stdin {
+			continue
+		}
+
+		b, err := snowboard.Read(input)
+		if err != nil {
+			return err
+		}
+
+		out, err := snowboard.Validate(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+
+		r := lintResult{input: input, src: b}
+
+		if out != nil {
+			var ns []api.Annotation
+
+			for _, n := range out.Annotations {
+				if n.Severity() == "error" {
+					ns = append(ns, n)
+				}
+			}
+
+			if len(ns) > 0 {
+				r.out = &api.API{Annotations: ns}
+				failed = true
+			}
+		}
+
+		rs = append(rs, r)
+	}
+
+	if !failed {
+		return nil
+	}
+
+	return invalidError{errors.New(annotationTable(rs, len(inputs) > 1))}
+}
+
+func lintFile(c *cli.Context, input string) (lintResult, error) {
+	b, err := snowboard.Read(input)
+	if err != nil {
+		return lintResult{}, err
+	}
+
+	out, err := snowboard.Validate(bytes.NewReader(b))
+	if err != nil {
+		return lintResult{}, err
+	}
+
+	if out, err = checkRules(c, b, out); err != nil {
+		return lintResult{}, err
+	}
+
+	return lintResult{input: input, src: b, out: out}, nil
+}
+
+// checkRules appends annotations of URI template, response and --check-examples checks, they are skipped when blueprint has errors.
+func checkRules(c *cli.Context, b []byte, out *api.API) (*api.API, error) {
+	if out != nil {
+		for _, n := range out.Annotations {
+			if n.Severity() == "error" {
+				return out, nil
+			}
+		}
+	}
+
+	allowed, err := allowedStatusCodes(c)
+	if err != nil {
+		return nil, err
+	}
+
+	bp, err := snowboard.ParseWithSourceMaps(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	ns := snowboard.CheckURITemplates(bp)
+	ns = append(ns, snowboard.CheckResponses(bp)...)
+	ns = append(ns, snowboard.CheckStatusCodes(bp, allowed)...)
+	ns = append(ns, snowboard.CheckDataStructures(bp)...)
+
+	if c.Bool("check-examples") {
+		ns = append(ns, snowboard.CheckExamples(bp)...)
+	}
+
+	if len(ns) == 0 {
+		return out, nil
+	}
+
+	if out == nil {
+		out = &api.API{}
+	}
+
+	out.Annotations = append(out.Annotations, ns...)
+	return out, nil
+}
+
+func allowedStatusCodes(c *cli.Context) ([]int, error) {
+	var xs []int
+
+	for _, v := range splitFlag(c.StringSlice("allow-status")) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid --allow-status value: %s", v)
+		}
+
+		xs = append(xs, n)
+	}
+
+	return xs, nil
+}
+
+// lintFailed reports whether annotations should fail the lint, warnings only fail with --fail-on-warnings.
+func lintFailed(c *cli.Context, ns []api.Annotation) bool {
+	for _, n := range ns {
+		if n.Severity() == "error" || c.Bool("fail-on-warnings") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lintSourceMap points at annotated source by 1-based line and column, offset and length are
+// byte counts of drafter source map
+type lintSourceMap struct {
+	Line   int `json:"line"`
+	Col    int `json:"col"`
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+type lintAnnotation struct {
+	File        string          `json:"file,omitempty"`
+	Description string          `json:"description"`
+	Severity    string          `json:"severity"`
+	Code        int             `json:"code"`
+	SourceMaps  []lintSourceMap `json:"sourceMaps"`
+}
+
+func validateJSON(c *cli.Context, rs []lintResult, multi bool) error {
+	xs := []lintAnnotation{}
+	failed := false
+
+	for _, r := range rs {
+		if r.out == nil {
+			continue
+		}
+
+		for _, n := range r.out.Annotations {
+			x := lintAnnotation{
+				Description: n.Description,
+				Severity:    n.Severity(),
+				Code:        n.Code,
+				SourceMaps:  []lintSourceMap{},
+			}
+
+			if multi {
+				x.File = r.input
+			}
+
+			for _, m := range n.SourceMaps {
+				line, col := snowboard.Position(r.src, m.Row)
+				x.SourceMaps = append(x.SourceMaps, lintSourceMap{Line: line, Col: col, Offset: m.Row, Length: m.Col})
+			}
+
+			xs = append(xs, x)
+		}
+
+		failed = failed || lintFailed(c, r.out.Annotations)
+	}
+
+	e := json.NewEncoder(c.App.Writer)
+	e.SetIndent("", "  ")
+
+	if err := e.Encode(xs); err != nil {
+		return err
+	}
+
+	if failed {
+		return exitErrorCode("", exitCodeInvalid)
+	}
+
+	return nil
+}
+
+func summarize(c *cli.Context, input string) error {
+	bp, err := snowboard.Load(input)
+	if err != nil {
+		return err
+	}
+
+	x := stats.Compute(bp)
+
+	if c.String("format") == "json" {
+		e := json.NewEncoder(c.App.Writer)
+		e.SetIndent("", "  ")
+		return e.Encode(x)
+	}
+
+	ms := make([]string, 0, len(x.Methods))
+	for m := range x.Methods {
+		ms = append(ms, m)
+	}
+
+	sort.Strings(ms)
+
+	w := tabwriter.NewWriter(c.App.Writer, 8, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Resource groups:\t%d\n", x.ResourceGroups)
+	fmt.Fprintf(w, "Resources:\t%d\n", x.Resources)
+	fmt.Fprintf(w, "Transitions:\t%d\n", x.Transitions)
+
+	for _, m := range ms {
+		fmt.Fprintf(w, "  %s:\t%d\n", m, x.Methods[m])
+	}
+
+	fmt.Fprintf(w, "Documented:\t%d\n", x.Documented)
+	fmt.Fprintf(w, "Undocumented:\t%d\n", x.Undocumented)
+	fmt.Fprintf(w, "Data structures:\t%d\n", x.DataStructures)
+
+	return w.Flush()
+}
+
+// queryElement prints subtree of input element json selected by selector, e.g.
+// `groups.0.resources.2.transitions`
+func queryElement(c *cli.Context, input, selector string) error {
+	b, err := snowboard.LoadAsJSON(input)
+	if err != nil {
+		return err
+	}
+
+	el, err := api.ParseJSON(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	x, err := el.Query(selector)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(x.Object(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.App.Writer, string(out))
+	return nil
+}
+
+// reportCoverage prints endpoints of input never hit by requests of access log name, `-` reads stdin
+func reportCoverage(c *cli.Context, input, name string) error {
+	bp, err := snowboard.Load(input)
+	if err != nil {
+		return err
+	}
+
+	r := io.Reader(os.Stdin)
+
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	x, err := coverage.Compute(bp, r, c.String("base-path"))
+	if err != nil {
+		return err
+	}
+
+	if c.String("format") == "json" {
+		e := json.NewEncoder(c.App.Writer)
+		e.SetIndent("", "  ")
+		return e.Encode(x)
+	}
+
+	fmt.Fprintf(c.App.Writer, "Covered %d of %d endpoints (%.1f%%)\n", x.Covered, x.Total, x.Percent)
+
+	if ms := x.Missing(); len(ms) > 0 {
+		fmt.Fprintln(c.App.Writer, "Never hit:")
+
+		for _, e := range ms {
+			fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorYellow, fmt.Sprintf("  %s\t%s", e.Method, e.Path)))
+		}
+	}
+
+	if x.Unmatched > 0 {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorDim, fmt.Sprintf("%d requests matched no endpoint", x.Unmatched)))
+	}
+
+	if x.Skipped > 0 {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorDim, fmt.Sprintf("%d lines are not in common or combined log format", x.Skipped)))
+	}
+
+	return nil
+}
+
+func diffAPI(c *cli.Context, before, after string) error {
+	bs, err := loadMulti([]string{before, after}, 2)
+	if err != nil {
+		return err
+	}
+
+	cs := diff.Compare(bs[0], bs[1])
+
+	if c.String("format") == "json" {
+		e := json.NewEncoder(c.App.Writer)
+		e.SetIndent("", "  ")
+
+		if err = e.Encode(cs); err != nil {
+			return err
+		}
+	} else {
+		diffTable(c, cs)
+	}
+
+	if diff.Breaking(cs) {
+		return errors.New("Potentially breaking changes found")
+	}
+
+	return nil
+}
+
+// renderChangelog writes Markdown changelog of changes turning before into after
+func renderChangelog(c *cli.Context, before, after, output string) error {
+	bs, err := loadMulti([]string{before, after}, 2)
+	if err != nil {
+		return err
+	}
+
+	md := diff.Changelog(diff.Compare(bs[0], bs[1]), c.String("title"))
+
+	if output == "" {
+		fmt.Fprint(c.App.Writer, md)
+		return nil
+	}
+
+	if err = ioutil.WriteFile(output, []byte(md), 0644); err != nil {
+		return err
+	}
+
+	if !c.Bool("q") {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorGreen, fmt.Sprintf("%s: Changelog has been generated!", output)))
+	}
+
+	return nil
+}
+
+func diffTable(c *cli.Context, cs []diff.Change) {
+	if len(cs) == 0 {
+		fmt.Fprintln(c.App.Writer, "No changes")
+		return
+	}
+
+	s := "--------"
+	w := tabwriter.NewWriter(c.App.Writer, 8, 0, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, "Change\tEndpoint\tItem\tDetail\tBreaking")
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s, strings.Repeat(s, 3), strings.Repeat(s, 2), strings.Repeat(s, 3), s)
+
+	for _, x := range cs {
+		b := ""
+		if x.Breaking {
+			b = "yes"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", x.Kind, x.Endpoint, x.Item, x.Detail, b)
+	}
+
+	w.Flush()
+}
+
+func dash(n int) string {
+	return strings.Repeat("-", n)
+}
+
+type fsWatcher interface {
+	Add(string) error
+}
+
+func outputName(c *cli.Context, output string) string {
+	switch c.Command.Name {
+	case "html":
+		if output == "" {
+			return "index.html"
+		}
+
+		return output
+	}
+
+	return ""
+}
+
+func actionCommand(c *cli.Context, input, output, tplFile string) error {
+	switch c.Command.Name {
+	case "html", "http":
+		if err := renderHTML(c, input, []string{output}, []string{tplFile}); err != nil {
+			return err
+		}
+	case "apib":
 		if err := renderAPIB(c, input, output); err != nil {
 			return err
 		}
@@ -423,64 +1891,698 @@ func actionCommand(c *cli.Context, input, output, tplFile string) error {
 		if err := renderJSON(c, input, output); err != nil {
 			return err
 		}
+	case "openapi":
+		if err := renderOpenAPI(c, input, output); err != nil {
+			return err
+		}
+	case "markdown":
+		if err := renderMarkdown(c, input, output); err != nil {
+			return err
+		}
+	case "postman":
+		if err := renderPostman(c, input, output); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func outputPath(c *cli.Context, inputs []string) error {
+	bs, err := loadMulti(inputs, c.Int("jobs"))
+	if err != nil {
+		return err
+	}
+
+	ms := mockRoutes(c, bs)
+
+	if err = checkDuplicates(c, inputs, ms); err != nil {
+		return err
+	}
+
+	if c.String("format") == "json" {
+		return printManifest(c, bs, c.String("sort"))
+	}
+
+	return printRoutes(c, ms, c.String("sort"))
+}
+
+// mockRoutes builds mock routes of blueprints, prefixed by --base-path
+func mockRoutes(c *cli.Context, bs []*api.API) []mock.MockTransactions {
+	return mock.WithBasePath(mock.MockMulti(bs), c.String("base-path"))
+}
+
+// checkDuplicates warns about routes defined by more than one input, the first input serves them
+func checkDuplicates(c *cli.Context, inputs []string, ms []mock.MockTransactions) error {
+	ds := mock.Duplicates(ms)
+
+	for _, d := range ds {
+		fs := make([]string, len(d.Sources))
+		for i, n := range d.Sources {
+			fs[i] = inputs[n]
+		}
+
+		fmt.Fprintln(c.App.ErrWriter, paint(c.App.ErrWriter, colorYellow, fmt.Sprintf("Duplicate route %s %s defined in %s, served from %s", d.Method, d.Path, strings.Join(fs, ", "), fs[0])))
+	}
+
+	if len(ds) > 0 && c.Bool("fail-on-duplicate") {
+		return errors.New("Duplicate routes found")
+	}
+
+	return nil
+}
+
+// manifestRoute describes a route of list JSON output
+type manifestRoute struct {
+	Group                string   `json:"group"`
+	Method               string   `json:"method"`
+	Path                 string   `json:"path"`
+	StatusCodes          []int    `json:"statusCodes"`
+	RequestContentTypes  []string `json:"requestContentTypes"`
+	ResponseContentTypes []string `json:"responseContentTypes"`
+}
+
+// printManifest prints routes as JSON, grouped by transition so transitions without examples are included.
+func printManifest(c *cli.Context, bs []*api.API, by string) error {
+	xs := []manifestRoute{}
+	idx := map[*api.Transition]int{}
+
+	for _, b := range bs {
+		for _, r := range api.Routes(b) {
+			i, ok := idx[r.Transition]
+			if !ok {
+				x := manifestRoute{
+					Group:                r.Group,
+					Method:               r.Method,
+					Path:                 r.Path,
+					StatusCodes:          []int{},
+					RequestContentTypes:  []string{},
+					ResponseContentTypes: []string{},
+				}
+
+				if base := c.String("base-path"); strings.Trim(base, "/") != "" {
+					x.Path = mock.JoinBasePath(base, x.Path)
+				}
+
+				i = len(xs)
+				idx[r.Transition] = i
+				xs = append(xs, x)
+			}
+
+			if n := r.Transaction; n != nil {
+				xs[i].StatusCodes = appendStatus(xs[i].StatusCodes, n.Response.StatusCode)
+				xs[i].RequestContentTypes = appendContentType(xs[i].RequestContentTypes, n.Request.Body.ContentType)
+				xs[i].ResponseContentTypes = appendContentType(xs[i].ResponseContentTypes, n.Response.Body.ContentType)
+			}
+		}
+	}
+
+	err := sortRoutes(xs, by, func(i int) (string, string, int) {
+		status := 0
+		if len(xs[i].StatusCodes) > 0 {
+			status = xs[i].StatusCodes[0]
+		}
+
+		return xs[i].Path, xs[i].Method, status
+	})
+	if err != nil {
+		return err
+	}
+
+	e := json.NewEncoder(c.App.Writer)
+	e.SetIndent("", "  ")
+	return e.Encode(xs)
+}
+
+// sortRoutes stably sorts slice xs of routes by path, method or status, ties are broken by the others
+// in that order. key returns path, method and status of the i-th route.
+func sortRoutes(xs interface{}, by string, key func(i int) (string, string, int)) error {
+	var order []int
+
+	switch by {
+	case "path":
+		order = []int{0, 1, 2}
+	case "method":
+		order = []int{1, 0, 2}
+	case "status":
+		order = []int{2, 0, 1}
+	default:
+		return fmt.Errorf("Unknown sort %q, use path, method or status", by)
 	}
 
+	sort.SliceStable(xs, func(i, j int) bool {
+		pi, mi, si := key(i)
+		pj, mj, sj := key(j)
+		ns := []int{strings.Compare(pi, pj), strings.Compare(mi, mj), si - sj}
+
+		for _, k := range order {
+			if ns[k] != 0 {
+				return ns[k] < 0
+			}
+		}
+
+		return false
+	})
+
 	return nil
 }
 
-func outputPath(c *cli.Context, inputs []string) error {
-	bs := make([]*api.API, len(inputs))
-	for i := range inputs {
-		bp, err := snowboard.Load(inputs[i])
-		if err != nil {
-			return err
+func appendStatus(xs []int, n int) []int {
+	for _, x := range xs {
+		if x == n {
+			return xs
 		}
+	}
+
+	return append(xs, n)
+}
+
+func appendContentType(xs []string, s string) []string {
+	if s == "" {
+		return xs
+	}
 
-		bs[i] = bp
+	for _, x := range xs {
+		if x == s {
+			return xs
+		}
 	}
-	ms := mock.MockMulti(bs)
+
+	return append(xs, s)
+}
+
+// printRoutes prints routes sorted by path, method or status, ties are broken by the others in that order.
+func printRoutes(c *cli.Context, ms []mock.MockTransactions, by string) error {
+	var xs []*mock.MockTransaction
+
 	for _, mm := range ms {
-		for _, m := range mm {
+		xs = append(xs, mm...)
+	}
+
+	err := sortRoutes(xs, by, func(i int) (string, string, int) {
+		return xs[i].Pattern, xs[i].Method, xs[i].StatusCode
+	})
+	if err != nil {
+		return err
+	}
+
+	if !c.Bool("grouped") {
+		for _, m := range xs {
 			fmt.Fprintf(c.App.Writer, "%s\t%d\t%s\n", m.Method, m.StatusCode, m.Pattern)
 		}
+
+		return nil
+	}
+
+	var groups []string
+	gs := map[string][]*mock.MockTransaction{}
+
+	for _, mm := range ms {
+		for _, m := range mm {
+			if _, ok := gs[m.Group]; !ok {
+				groups = append(groups, m.Group)
+				gs[m.Group] = nil
+			}
+		}
+	}
+
+	for _, m := range xs {
+		gs[m.Group] = append(gs[m.Group], m)
+	}
+
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Fprintln(c.App.Writer)
+		}
+
+		title := g
+		if title == "" {
+			title = "(ungrouped)"
+		}
+
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorCyan, title))
+
+		for _, m := range gs[g] {
+			fmt.Fprintf(c.App.Writer, "  %s\t%d\t%s\n", m.Method, m.StatusCode, m.Pattern)
+		}
 	}
+
 	return nil
 }
 
-func serveHTML(c *cli.Context, bind, output string) error {
-	fmt.Fprintf(c.App.Writer, "snowboard: listening on %s\n", bind)
+// loadMulti loads blueprints using at most jobs workers, results keep inputs order.
+func loadMulti(inputs []string, jobs int) ([]*api.API, error) {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	bs := make([]*api.API, len(inputs))
+	es := make([]error, len(inputs))
+	ch := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for n := 0; n < jobs; n++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range ch {
+				bs[i], es[i] = snowboard.Load(inputs[i])
+			}
+		}()
+	}
+
+	for i := range inputs {
+		ch <- i
+	}
+
+	close(ch)
+	wg.Wait()
+
+	var ms []string
+
+	for i, err := range es {
+		if err != nil {
+			ms = append(ms, fmt.Sprintf("%s: %s", inputs[i], err))
+		}
+	}
+
+	if len(ms) > 0 {
+		return nil, errors.New(strings.Join(ms, "\n"))
+	}
+
+	return bs, nil
+}
+
+// bindAddr returns the listen address, falling back to env then PORT when -b flag is left at its default.
+func bindAddr(c *cli.Context, env string) string {
+	if c.IsSet("b") {
+		return c.String("b")
+	}
+
+	if s := os.Getenv(env); s != "" {
+		return s
+	}
+
+	if s := os.Getenv("PORT"); s != "" {
+		return ":" + s
+	}
+
+	return c.String("b")
+}
+
+// serveHTML serves output, with live reload script injected when lr is set
+func serveHTML(c *cli.Context, bind, output string, lr *liveReload) error {
+	cfg, err := tlsConfig(c)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorCyan, fmt.Sprintf("snowboard: listening on %s", bind)))
+
+	if c.Bool("open") {
+		go openBrowser(c, bind, cfg != nil)
+	}
+
+	if lr != nil {
+		http.Handle(liveReloadPath, lr)
+	}
 
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, output)
+		serveWithETag(w, r, output, lr != nil)
 	})
 
-	return http.ListenAndServe(bind, nil)
+	return listenAndServe(bind, nil, cfg)
+}
+
+// serveLiveHTML serves documentation of input rendered by liveHTML, without writing any file
+func serveLiveHTML(c *cli.Context, bind, input, tplFile string, lr *liveReload) error {
+	cfg, err := tlsConfig(c)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorCyan, fmt.Sprintf("snowboard: listening on %s", bind)))
+
+	if c.Bool("open") {
+		go openBrowser(c, bind, cfg != nil)
+	}
+
+	if lr != nil {
+		http.Handle(liveReloadPath, lr)
+	}
+
+	http.Handle("/", newLiveHTML(c, input, tplFile, lr != nil))
+
+	return listenAndServe(bind, nil, cfg)
+}
+
+// serveWithETag serves file tagged by its content hash, so unchanged documentation is answered with 304 on reload
+func serveWithETag(w http.ResponseWriter, r *http.Request, name string, live bool) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		http.ServeFile(w, r, name)
+		return
+	}
+
+	if live {
+		b = injectLiveReload(b)
+	}
+
+	fi, err := os.Stat(name)
+	if err != nil {
+		http.ServeFile(w, r, name)
+		return
+	}
+
+	w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha1.Sum(b)))
+	http.ServeContent(w, r, fi.Name(), fi.ModTime(), bytes.NewReader(b))
 }
 
 func serveMock(c *cli.Context, bind string, inputs []string) error {
-	bs := make([]*api.API, len(inputs))
+	cfg, err := tlsConfig(c)
+	if err != nil {
+		return err
+	}
 
-	for i := range inputs {
-		bp, err := snowboard.Load(inputs[i])
+	bs, err := loadMulti(inputs, c.Int("jobs"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorCyan, fmt.Sprintf("Mock server is ready. Use %s", bind)))
+	fmt.Fprintln(c.App.Writer, "Available Routes:")
+
+	ms := mockRoutes(c, bs)
+	if err = checkDuplicates(c, inputs, ms); err != nil {
+		return err
+	}
+
+	if c.Bool("dynamic") {
+		if err = mock.CheckFakers(ms); err != nil {
+			return err
+		}
+	}
+
+	if err = printRoutes(c, ms, "path"); err != nil {
+		return err
+	}
+
+	opt := mock.Options{
+		StrictRequest:    c.Bool("strict-request"),
+		Delay:            c.Duration("delay"),
+		ParamPlaceholder: c.String("param-placeholder"),
+		Replay:           c.Bool("replay"),
+		UpstreamTimeout:  c.Duration("upstream-timeout"),
+		UpstreamFallback: c.Bool("upstream-fallback"),
+		GzipMinLength:    c.Int("gzip-min-length"),
+		NotFoundBody:     c.String("not-found-body"),
+		AdminPrefix:      c.String("admin-prefix"),
+		CycleExamples:    c.Bool("cycle-examples"),
+		Dynamic:          c.Bool("dynamic"),
+		IndentXML:        c.Bool("indent-xml"),
+		MaxBodySize:      c.Int64("max-body-size"),
+	}
+
+	if c.Bool("stateful") {
+		opt.State = mock.NewState()
+	}
+
+	if opt.RateLimit, err = mock.ParseRateLimit(c.String("rate-limit")); err != nil {
+		return err
+	}
+
+	if opt.BasicAuth, err = mock.ParseBasicAuth(c.String("basic-auth")); err != nil {
+		return err
+	}
+
+	if opt.MediaTypes, err = mock.ParseMediaTypes(c.StringSlice("media-type")); err != nil {
+		return err
+	}
+
+	if s := c.String("proxy"); s != "" {
+		u, err := url.Parse(s)
 		if err != nil {
 			return err
 		}
 
-		bs[i] = bp
+		opt.Proxy = u
 	}
 
-	fmt.Fprintf(c.App.Writer, "Mock server is ready. Use %s\n", bind)
-	fmt.Fprintln(c.App.Writer, "Available Routes:")
+	if s := c.String("cassette"); s != "" {
+		cs, err := mock.LoadCassette(s)
+		if err != nil {
+			return err
+		}
 
-	ms := mock.MockMulti(bs)
-	for _, mm := range ms {
-		for _, m := range mm {
-			fmt.Fprintf(c.App.Writer, "%s\t%d\t%s\n", m.Method, m.StatusCode, m.Pattern)
+		opt.Cassette = cs
+	}
+
+	if opt.Replay && opt.Cassette == nil {
+		return errors.New("Replay requires cassette, use --cassette flag")
+	}
+
+	h := mock.NewReloader(mock.MockHandler(ms, opt))
+	z := cors.New(corsOptions(c)).Handler(h)
+
+	if d := c.Duration("reload-interval"); d > 0 && hasStdin(inputs) {
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorCyan, "Reload is disabled for stdin input, it can only be read once"))
+	} else if d > 0 {
+		for _, input := range inputs {
+			if loader.IsURL(input) {
+				fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorCyan, fmt.Sprintf("Reload is disabled for URL input %s", input)))
+			}
+		}
+
+		noteRemoteIncludes(c, inputs)
+
+		go reloadMock(c, inputs, d, h, opt)
+	}
+
+	return listenAndServe(bind, z, cfg)
+}
+
+// reloadMock polls blueprints, their seeds and includes, swapping mock routes when any of them changes.
+// Seeds and includes are listed again after every reload, so newly included files are picked up as well.
+// Changes within --debounce coalesce into one reload.
+func reloadMock(c *cli.Context, inputs []string, d time.Duration, h *mock.Reloader, opt mock.Options) {
+	fs := watchedFiles(inputs)
+	last := modTimes(fs)
+
+	t := time.NewTicker(d)
+	defer t.Stop()
+
+	for range t.C {
+		mt := modTimes(fs)
+		if sameModTimes(last, mt) {
+			continue
+		}
+
+		last = settle(c.Duration("debounce"), mt, func() map[string]time.Time { return modTimes(fs) })
+
+		// seeds and includes may have been added or removed, mod times are taken before loading so
+		// changes made while reloading are picked up by the next poll
+		next := watchedFiles(inputs)
+		nt := modTimes(next)
+
+		bs, err := loadMulti(inputs, c.Int("jobs"))
+		if err != nil {
+			fmt.Fprintln(c.App.ErrWriter, paint(c.App.ErrWriter, colorRed, fmt.Sprintf("[%s] Mock routes reload failed: %s", time.Now().Format(time.RFC3339), err)))
+			continue
+		}
+
+		ms := mockRoutes(c, bs)
+		if opt.Dynamic {
+			if err = mock.CheckFakers(ms); err != nil {
+				fmt.Fprintln(c.App.ErrWriter, paint(c.App.ErrWriter, colorRed, fmt.Sprintf("[%s] Mock routes reload failed: %s", time.Now().Format(time.RFC3339), err)))
+				continue
+			}
+		}
+
+		fs, last = next, nt
+
+		h.Swap(mock.MockHandler(ms, opt))
+		fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorDim, fmt.Sprintf("[%s] Mock routes have been reloaded!", time.Now().Format(time.RFC3339))))
+	}
+}
+
+func hasStdin(inputs []string) bool {
+	for _, input := range inputs {
+		if input == loader.Stdin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// noteRemoteIncludes tells that remote includes of inputs are not watched, changes are only picked
+// up along with local ones once their cached copy expires
+func noteRemoteIncludes(c *cli.Context, inputs []string) {
+	for _, input := range inputs {
+		for _, u := range loader.RemoteIncludes(input) {
+			fmt.Fprintln(c.App.Writer, paint(c.App.Writer, colorCyan, fmt.Sprintf("Remote include %s is not watched", u)))
+		}
+	}
+}
+
+// corsOptions builds CORS policy from flags, unset flags keep the allow-all defaults.
+func corsOptions(c *cli.Context) cors.Options {
+	opt := cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"HEAD", "GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders: []string{"*"},
+	}
+
+	if xs := splitFlag(c.StringSlice("cors-origin")); len(xs) > 0 {
+		opt.AllowedOrigins = xs
+	}
+
+	if xs := splitFlag(c.StringSlice("cors-methods")); len(xs) > 0 {
+		opt.AllowedMethods = xs
+	}
+
+	if xs := splitFlag(c.StringSlice("cors-headers")); len(xs) > 0 {
+		opt.AllowedHeaders = xs
+	}
+
+	return opt
+}
+
+// firstFlag returns the first value of repeatable flag, or fallback when it is not given
+func firstFlag(c *cli.Context, name, fallback string) string {
+	if xs := c.StringSlice(name); len(xs) > 0 {
+		return xs[0]
+	}
+
+	return fallback
+}
+
+// overrideHost replaces HOST of the blueprint with --host flag, if any
+func overrideHost(c *cli.Context, bp *api.API) {
+	if h := c.String("host"); h != "" {
+		bp.SetHost(h)
+	}
+}
+
+// groupFilters returns --only and --exclude resource group titles
+func groupFilters(c *cli.Context) ([]string, []string) {
+	return splitFlag(c.StringSlice("only")), splitFlag(c.StringSlice("exclude"))
+}
+
+func splitFlag(vs []string) []string {
+	var xs []string
+
+	for _, v := range vs {
+		for _, x := range strings.Split(v, ",") {
+			if x = strings.TrimSpace(x); x != "" {
+				xs = append(xs, x)
+			}
+		}
+	}
+
+	return xs
+}
+
+// tlsConfig builds TLS configuration from flags, it returns nil for plain HTTP.
+func tlsConfig(c *cli.Context) (*tls.Config, error) {
+	cert, key := c.String("tls-cert"), c.String("tls-key")
+
+	if (cert == "") != (key == "") {
+		return nil, errors.New("TLS requires both --tls-cert and --tls-key")
+	}
+
+	if cert != "" && c.Bool("self-signed") {
+		return nil, errors.New("--self-signed cannot be combined with --tls-cert and --tls-key")
+	}
+
+	if cert != "" {
+		z, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, err
+		}
+
+		return &tls.Config{Certificates: []tls.Certificate{z}}, nil
+	}
+
+	if c.Bool("self-signed") {
+		z, err := selfSignedCertificate()
+		if err != nil {
+			return nil, err
 		}
+
+		return &tls.Config{Certificates: []tls.Certificate{z}}, nil
+	}
+
+	return nil, nil
+}
+
+func selfSignedCertificate() (tls.Certificate, error) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	sn, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	n := time.Now()
+	t := &x509.Certificate{
+		SerialNumber: sn,
+		Subject:      pkix.Name{Organization: []string{"snowboard"}},
+		NotBefore:    n.Add(-time.Hour),
+		NotAfter:     n.Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	b, err := x509.CreateCertificate(rand.Reader, t, t, &k.PublicKey, k)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{b}, PrivateKey: k}, nil
+}
+
+// shutdownTimeout is the grace period given to in-flight requests on SIGINT or SIGTERM
+const shutdownTimeout = 5 * time.Second
+
+// listenAndServe serves until SIGINT or SIGTERM is received, then shuts the server down gracefully.
+func listenAndServe(bind string, h http.Handler, cfg *tls.Config) error {
+	s := &http.Server{Addr: bind, Handler: h, TLSConfig: cfg}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	done := make(chan error, 1)
+
+	go func() {
+		<-sig
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		done <- s.Shutdown(ctx)
+	}()
+
+	var err error
+
+	if cfg == nil {
+		err = s.ListenAndServe()
+	} else {
+		err = s.ListenAndServeTLS("", "")
 	}
 
-	h := mock.MockHandler(ms)
-	z := cors.AllowAll().Handler(h)
+	if err != http.ErrServerClosed {
+		return err
+	}
 
-	return http.ListenAndServe(bind, z)
+	return <-done
 }
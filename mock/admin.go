@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type adminRoute struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	StatusCode int    `json:"statusCode"`
+}
+
+// adminHandler serves health and route table of the mock server itself under prefix
+func adminHandler(prefix string, ms []MockTransactions) func(w http.ResponseWriter, r *http.Request) bool {
+	rs := []adminRoute{}
+
+	for _, mm := range ms {
+		for _, m := range mm {
+			rs = append(rs, adminRoute{Method: m.Method, Path: m.Pattern, StatusCode: m.StatusCode})
+		}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		if prefix == "" || r.Method != http.MethodGet {
+			return false
+		}
+
+		switch r.URL.Path {
+		case prefix + "health":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status":"ok"}`))
+		case prefix + "routes":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(rs)
+		default:
+			return false
+		}
+
+		return true
+	}
+}
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// BasicAuth requires requests to carry HTTP basic credentials of Username and Password
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// ParseBasicAuth parses `<user>:<pass>`, the password may contain colons. Empty string disables
+// authentication and returns nil.
+func ParseBasicAuth(s string) (*BasicAuth, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	z := strings.SplitN(s, ":", 2)
+	if len(z) != 2 || z[0] == "" {
+		return nil, fmt.Errorf("invalid basic auth: %s, expected <user>:<pass>", s)
+	}
+
+	return &BasicAuth{Username: z[0], Password: z[1]}, nil
+}
+
+// deny responds 401 with WWW-Authenticate header when r carries missing or wrong credentials
+func (a *BasicAuth) deny(w http.ResponseWriter, r *http.Request) bool {
+	u, p, ok := r.BasicAuth()
+	if ok && secureEqual(u, a.Username) && secureEqual(p, a.Password) {
+		return false
+	}
+
+	w.Header().Set("WWW-Authenticate", `Basic realm="snowboard", charset="UTF-8"`)
+	writeMessage(w, http.StatusUnauthorized)
+
+	return true
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"regexp"
+	"sort"
+)
+
+// Duplicate is a route defined by more than one blueprint, requests are served by the first one
+type Duplicate struct {
+	Method string
+	Path   string
+	// Sources are indexes of blueprints defining the route, in MockMulti input order
+	Sources []int
+}
+
+var routeParamPattern = regexp.MustCompile(`([:*])\w+`)
+
+// Duplicates lists routes defined by more than one blueprint of ms, sorted by path then method.
+// Routes differing only in parameter names, e.g. `/users/{id}` and `/users/{user_id}`, collide.
+func Duplicates(ms []MockTransactions) []Duplicate {
+	idx := map[string]*Duplicate{}
+	var keys []string
+
+	for i, mm := range ms {
+		for _, m := range mm {
+			k := m.Method + " " + routeParamPattern.ReplaceAllString(m.Path, "$1")
+
+			d, ok := idx[k]
+			if !ok {
+				d = &Duplicate{Method: m.Method, Path: m.Path}
+				idx[k] = d
+				keys = append(keys, k)
+			}
+
+			if n := len(d.Sources); n == 0 || d.Sources[n-1] != i {
+				d.Sources = append(d.Sources, i)
+			}
+		}
+	}
+
+	ds := []Duplicate{}
+
+	for _, k := range keys {
+		if d := idx[k]; len(d.Sources) > 1 {
+			ds = append(ds, *d)
+		}
+	}
+
+	sort.SliceStable(ds, func(i, j int) bool {
+		if ds[i].Path != ds[j].Path {
+			return ds[i].Path < ds[j].Path
+		}
+
+		return ds[i].Method < ds[j].Method
+	})
+
+	return ds
+}
@@ -0,0 +1,21 @@
+package mock
+
+import (
+	"fmt"
+
+	"github.com/bukalapak/snowboard/faker"
+)
+
+// CheckFakers fails on response bodies of ms having `{{faker.<name>}}` directives that Dynamic
+// cannot expand, naming the route declaring them
+func CheckFakers(ms []MockTransactions) error {
+	for _, mm := range ms {
+		for _, m := range mm {
+			if err := faker.Check(m.Body); err != nil {
+				return fmt.Errorf("%s %s: %s", m.Method, m.Path, err)
+			}
+		}
+	}
+
+	return nil
+}
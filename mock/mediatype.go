@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// MediaTypes maps custom media types to the base type they are handled as, e.g.
+// `application/vnd.company.v2` to `application/json`
+type MediaTypes map[string]string
+
+// ParseMediaTypes parses `<type>=<base>` pairs, e.g. `application/vnd.company.v2=application/json`
+func ParseMediaTypes(xs []string) (MediaTypes, error) {
+	m := MediaTypes{}
+
+	for _, x := range xs {
+		z := strings.SplitN(x, "=", 2)
+		if len(z) != 2 || !strings.Contains(z[0], "/") || !strings.Contains(z[1], "/") {
+			return nil, fmt.Errorf("invalid media type mapping: %s, expected <type>=<base>", x)
+		}
+
+		m[strings.ToLower(strings.TrimSpace(z[0]))] = strings.ToLower(strings.TrimSpace(z[1]))
+	}
+
+	return m, nil
+}
+
+// Base returns the base type of content type without parameters. Registered types are looked up
+// first, then structured syntax suffixes `+json` and `+xml` map to `application/json` and
+// `application/xml`, as does `text/xml`. Other types are their own base.
+func (m MediaTypes) Base(contentType string) string {
+	t, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	if b, ok := m[t]; ok {
+		return b
+	}
+
+	switch {
+	case strings.HasSuffix(t, "+json"):
+		return "application/json"
+	case strings.HasSuffix(t, "+xml"), t == "text/xml":
+		return "application/xml"
+	}
+
+	return t
+}
+
+// compressible reports whether body of content type is worth compressing, bodies of unknown
+// content type are assumed to be text
+func (m MediaTypes) compressible(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	t := m.Base(contentType)
+
+	switch t {
+	case "application/json", "application/xml", "application/javascript", "application/x-www-form-urlencoded":
+		return true
+	}
+
+	return strings.HasPrefix(t, "text/")
+}
@@ -1,30 +1,106 @@
 package mock
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/faker"
+	"github.com/bukalapak/snowboard/schema"
 	"github.com/naoina/denco"
 )
 
 type MockTransaction struct {
-	Path        string
-	Pattern     string
-	Method      string
-	StatusCode  int
-	ContentType string
-	Body        string
+	// Group is the title of the resource group declaring the transaction
+	Group         string
+	Path          string
+	Pattern       string
+	Method        string
+	StatusCode    int
+	ContentType   string
+	Body          string
+	RequestSchema string
+	Delay         time.Duration
+	Headers       []api.Header
+	// Example is the name of the request example, e.g. `Full` of `+ Request Full`
+	Example string
+	// Query maps declared query parameters to their example values, e.g. `type=image` of `/search{?type}`
+	Query map[string]string
 }
 
+// Options configures MockHandler behaviour
+type Options struct {
+	// StrictRequest validates request body against the request schema of the transition
+	StrictRequest bool
+	// Delay postpones every response, transactions can override it using X-Mock-Delay header
+	Delay time.Duration
+	// ParamPlaceholder is the format of path parameter placeholder in response body, defaults to DefaultParamPlaceholder
+	ParamPlaceholder string
+	// Proxy forwards requests without matching blueprint example to the upstream
+	Proxy *url.URL
+	// Cassette records responses captured by Proxy
+	Cassette *Cassette
+	// Replay serves responses from Cassette before looking up the blueprint
+	Replay bool
+	// UpstreamTimeout cancels requests to Proxy taking longer, responding 504. Zero waits forever.
+	UpstreamTimeout time.Duration
+	// UpstreamFallback sends every request to Proxy first, serving the blueprint example of the
+	// route when it fails or times out, or the response recorded in Cassette for undocumented routes
+	UpstreamFallback bool
+	// Dynamic expands `{{faker.<name>}}` directives in response body on every request
+	Dynamic bool
+	// CycleExamples rotates through examples sharing the selected status code on successive requests
+	CycleExamples bool
+	// GzipMinLength is the minimum body length compressed for clients accepting gzip, defaults to DefaultGzipMinLength
+	GzipMinLength int
+	// NotFoundBody replaces JSON body suggesting closest routes, responded to unmatched requests
+	NotFoundBody string
+	// State serves resources created by POST requests back to GET and DELETE requests, nil disables it
+	State *State
+	// MediaTypes maps custom media types to base types for content negotiation, gzip and request
+	// validation. Structured syntax suffixes like `+json` are recognized without it.
+	MediaTypes MediaTypes
+	// AdminPrefix serves `<prefix>health` and `<prefix>routes` of the mock server itself ahead of
+	// blueprint routes, e.g. `/__` for `/__health`. Empty disables them.
+	AdminPrefix string
+	// RateLimit responds 429 with Retry-After header to clients exceeding it, nil disables it
+	RateLimit *RateLimit
+	// BasicAuth responds 401 with WWW-Authenticate header to requests without its credentials,
+	// nil disables it. Admin endpoints stay open.
+	BasicAuth *BasicAuth
+	// IndentXML indents XML response bodies, bodies failing to parse are served as written
+	IndentXML bool
+	// MaxBodySize is the maximum request body length in bytes, larger requests are responded 413.
+	// Defaults to DefaultMaxBodySize.
+	MaxBodySize int64
+}
+
+// DefaultGzipMinLength leaves bodies shorter than 1 KB uncompressed
+const DefaultGzipMinLength = 1024
+
+// DefaultMaxBodySize rejects request bodies larger than 10 MB
+const DefaultMaxBodySize = 10 << 20
+
+// DefaultParamPlaceholder substitutes `{id}` in response body with the value of `id` path parameter
+const DefaultParamPlaceholder = "{%s}"
+
 type mockRecord struct {
+	// counter is accessed atomically, keep it first for 64-bit alignment
+	counter      uint64
 	Pattern      string
 	Method       string
 	Transactions []*MockTransaction
@@ -42,6 +118,27 @@ func (mr mockRouter) Router(method string) *denco.Router {
 	return nil
 }
 
+// allowedMethods lists sorted methods having a route matching path
+func allowedMethods(mr []*mockRouter, path string) []string {
+	seen := map[string]bool{}
+
+	for _, q := range mr {
+		for k, router := range q.routers {
+			if _, _, ok := router.Lookup(path); ok {
+				seen[k] = true
+			}
+		}
+	}
+
+	ms := make([]string, 0, len(seen))
+	for k := range seen {
+		ms = append(ms, k)
+	}
+
+	sort.Strings(ms)
+	return ms
+}
+
 type MockTransactions []*MockTransaction
 
 func (ms MockTransactions) Router() *mockRouter {
@@ -90,29 +187,61 @@ func (ms MockTransactions) Router() *mockRouter {
 func Mock(b *api.API) []*MockTransaction {
 	ms := []*MockTransaction{}
 
-	for _, g := range b.ResourceGroups {
-		for _, x := range g.Resources {
-			for _, t := range x.Transitions {
-				for _, n := range t.Transactions {
-					p := transformURL(t.URL, b.Host())
-					m := &MockTransaction{
-						Path:        urlPath(p),
-						Pattern:     p,
-						Method:      n.Request.Method,
-						StatusCode:  n.Response.StatusCode,
-						ContentType: n.Response.Body.ContentType,
-						Body:        n.Response.Body.Body,
-					}
+	for _, x := range api.Routes(b) {
+		n := x.Transaction
+		if n == nil {
+			continue
+		}
 
-					ms = append(ms, m)
-				}
-			}
+		p := transformURL(x.URL, b.Host())
+		m := &MockTransaction{
+			Group:         x.Group,
+			Path:          urlPath(p),
+			Pattern:       p,
+			Method:        n.Request.Method,
+			StatusCode:    n.Response.StatusCode,
+			ContentType:   n.Response.Body.ContentType,
+			Body:          n.Response.Body.Body,
+			RequestSchema: n.Request.Schema.Body,
+			Delay:         mockDelay(n.Response.Headers),
+			Headers:       n.Response.Headers,
+			Example:       n.Request.Title,
+			Query:         queryExamples(x),
 		}
+
+		ms = append(ms, m)
 	}
 
 	return ms
 }
 
+// WithBasePath returns copies of ms whose routes are prefixed with base
+func WithBasePath(ms []MockTransactions, base string) []MockTransactions {
+	if strings.Trim(base, "/") == "" {
+		return ms
+	}
+
+	xs := make([]MockTransactions, len(ms))
+
+	for i, mm := range ms {
+		xs[i] = make(MockTransactions, len(mm))
+
+		for j, m := range mm {
+			x := *m
+			x.Path = JoinBasePath(base, m.Path)
+			x.Pattern = JoinBasePath(base, m.Pattern)
+			xs[i][j] = &x
+		}
+	}
+
+	return xs
+}
+
+// JoinBasePath prefixes path p with base, slashes are normalized so `/api/v2/` and `users` yield `/api/v2/users`
+func JoinBasePath(base, p string) string {
+	return path.Join("/", base, p)
+}
+
 func MockMulti(bs []*api.API) []MockTransactions {
 	ms := make([]MockTransactions, len(bs))
 
@@ -123,68 +252,462 @@ func MockMulti(bs []*api.API) []MockTransactions {
 	return ms
 }
 
-func MockHandler(ms []MockTransactions) http.Handler {
+func MockHandler(ms []MockTransactions, opt Options) http.Handler {
 	mr := make([]*mockRouter, len(ms))
 
 	for i := range ms {
 		mr[i] = ms[i].Router()
 	}
 
-	fn := func(w http.ResponseWriter, r *http.Request) {
-		var n *MockTransaction
-
-		var found bool
-		var data interface{}
+	rs := knownRoutes(ms)
+	admin := adminHandler(opt.AdminPrefix, ms)
 
+	lookup := func(r *http.Request) (*mockRecord, denco.Params, bool) {
 		for _, q := range mr {
 			if router := q.Router(r.Method); router != nil {
-				data, _, found = router.Lookup(r.URL.Path)
+				if data, params, found := router.Lookup(r.URL.EscapedPath()); found {
+					return data.(*mockRecord), params, true
+				}
 			}
 		}
 
-		if !found {
+		return nil, nil, false
+	}
+
+	respond := func(w http.ResponseWriter, r *http.Request, m *mockRecord, params denco.Params) {
+		if opt.StrictRequest {
+			if ct := r.Header.Get("Content-Type"); ct != "" && opt.MediaTypes.Base(ct) != "application/json" && requestSchema(m) != "" {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+
+			if errs := validateRequest(m, r); len(errs) > 0 {
+				invalidRequest(w, errs)
+				return
+			}
+		}
+
+		n, ok := selectTransaction(m, r, opt.MediaTypes, opt.CycleExamples)
+
+		if !ok {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		if n == nil {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
 
-		m := data.(*mockRecord)
-		s := preferStatusCode(r)
+		d := opt.Delay
+		if n.Delay > 0 {
+			d = n.Delay
+		}
 
-		if s == "" {
-			for _, t := range m.Transactions {
-				if t.StatusCode >= http.StatusOK && t.StatusCode < http.StatusBadRequest {
-					n = t
-				}
+		if !wait(r, d) {
+			return
+		}
+
+		code, ct := n.StatusCode, n.ContentType
+
+		body := expandParams(n.Body, params, opt.ParamPlaceholder)
+		if opt.Dynamic {
+			body = faker.Expand(body)
+		}
+
+		if opt.State != nil {
+			if c, b, ok := opt.State.serve(r, n); ok {
+				code, body, ct = c, b, "application/json"
 			}
-		} else {
-			for _, t := range m.Transactions {
-				if s == strconv.Itoa(t.StatusCode) {
-					n = t
+		}
+
+		if opt.IndentXML && opt.MediaTypes.Base(ct) == "application/xml" {
+			if x, err := api.IndentXML(body); err == nil {
+				body = x
+			}
+		}
+
+		log.Printf("%s\t%d\t%s\n", n.Method, code, n.Path)
+
+		for _, h := range n.Headers {
+			if !reservedHeader(h.Key) {
+				w.Header().Add(h.Key, h.Value)
+			}
+		}
+
+		w.Header().Set("Content-Type", ct)
+		writeBody(w, r, code, body, opt.GzipMinLength, opt.MediaTypes.compressible(ct))
+	}
+
+	var px http.Handler
+	if opt.Proxy != nil {
+		var fallback func(w http.ResponseWriter, r *http.Request) bool
+
+		if opt.UpstreamFallback {
+			fallback = func(w http.ResponseWriter, r *http.Request) bool {
+				m, params, ok := lookup(r)
+				if ok {
+					respond(w, r, m, params)
 				}
+
+				return ok
 			}
 		}
 
-		if n == nil {
-			w.WriteHeader(http.StatusNotFound)
+		px = newProxy(opt.Proxy, opt.Cassette, fallback)
+	}
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if admin(w, r) {
+			return
+		}
+
+		if opt.RateLimit != nil && opt.RateLimit.limit(w, r) {
+			return
+		}
+
+		if opt.BasicAuth != nil && opt.BasicAuth.deny(w, r) {
+			return
+		}
+
+		if !limitBody(w, r, opt.MaxBodySize) {
+			return
+		}
+
+		if opt.Replay && opt.Cassette != nil {
+			if e := opt.Cassette.Find(r); e != nil {
+				log.Printf("%s\t%d\t%s (replay)\n", e.Method, e.StatusCode, e.URL)
+				e.serve(w)
+				return
+			}
+		}
+
+		// upstream is tried first when blueprint examples serve as fallback
+		if px != nil && opt.UpstreamFallback {
+			proxy(px, w, r, opt.UpstreamTimeout)
 			return
 		}
 
-		log.Printf("%s\t%d\t%s\n", n.Method, n.StatusCode, n.Path)
+		m, params, found := lookup(r)
+
+		if !found {
+			if px != nil {
+				proxy(px, w, r, opt.UpstreamTimeout)
+				return
+			}
+
+			if ms := allowedMethods(mr, r.URL.EscapedPath()); len(ms) > 0 {
+				w.Header().Set("Allow", strings.Join(ms, ", "))
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+
+			notFound(w, r, rs, opt.NotFoundBody)
+			return
+		}
 
-		w.Header().Set("Content-Type", n.ContentType)
-		w.WriteHeader(n.StatusCode)
-		io.WriteString(w, n.Body)
+		respond(w, r, m, params)
 	}
 
 	return http.HandlerFunc(fn)
 }
 
+// selectTransaction picks the response for a request. Examples whose declared
+// query parameters match the request query are considered first. Examples named by
+// X-Mock-Example header are considered first, falling back to all examples
+// when none matches. Among them, status code requested via Prefer (or
+// X-Status-Code) header takes precedence, otherwise successful
+// responses are considered. Among them, the response whose content type best
+// matches Accept header, or base type of it in mt, is used; when several examples match equally, the
+// first one declared in the blueprint wins, unless cycle is set which rotates
+// through examples sharing its status code. It returns false when none of
+// the candidates is acceptable.
+func selectTransaction(m *mockRecord, r *http.Request, mt MediaTypes, cycle bool) (*MockTransaction, bool) {
+	ts := candidateTransactions(m, r)
+
+	if len(ts) == 0 {
+		return nil, true
+	}
+
+	if a := r.Header.Get("Accept"); a != "" {
+		if ts = negotiate(ts, mt, parseAccept(a)); len(ts) == 0 {
+			return nil, false
+		}
+	}
+
+	if !cycle {
+		return ts[0], true
+	}
+
+	var xs []*MockTransaction
+
+	for _, t := range ts {
+		if t.StatusCode == ts[0].StatusCode {
+			xs = append(xs, t)
+		}
+	}
+
+	n := atomic.AddUint64(&m.counter, 1) - 1
+	return xs[n%uint64(len(xs))], true
+}
+
+// negotiate keeps transactions with the highest acceptable quality
+func negotiate(ts []*MockTransaction, mt MediaTypes, rs []mediaRange) []*MockTransaction {
+	var xs []*MockTransaction
+	var q float64
+
+	for _, t := range ts {
+		z := acceptQuality(rs, mt, t.ContentType)
+
+		switch {
+		case z > q:
+			xs, q = []*MockTransaction{t}, z
+		case z == q && z > 0:
+			xs = append(xs, t)
+		}
+	}
+
+	return xs
+}
+
+func candidateTransactions(m *mockRecord, r *http.Request) []*MockTransaction {
+	var ts []*MockTransaction
+
+	all := matchQuery(m.Transactions, r.URL.Query())
+
+	if x := strings.TrimSpace(r.Header.Get("X-Mock-Example")); x != "" {
+		for _, t := range all {
+			if strings.EqualFold(t.Example, x) {
+				ts = append(ts, t)
+			}
+		}
+
+		if len(ts) > 0 {
+			all, ts = ts, nil
+		}
+	}
+
+	if s := preferStatusCode(r); s != "" {
+		for _, t := range all {
+			if s == strconv.Itoa(t.StatusCode) {
+				ts = append(ts, t)
+			}
+		}
+	}
+
+	if len(ts) > 0 {
+		return ts
+	}
+
+	for _, t := range all {
+		if t.StatusCode >= http.StatusOK && t.StatusCode < http.StatusBadRequest {
+			ts = append(ts, t)
+		}
+	}
+
+	if len(ts) > 0 {
+		return ts
+	}
+
+	return all
+}
+
+// writeBody writes response body, compressing it with gzip when the client
+// accepts it, the body is compressible and at least min bytes long.
+func writeBody(w http.ResponseWriter, r *http.Request, code int, body string, min int, compress bool) {
+	if min <= 0 {
+		min = DefaultGzipMinLength
+	}
+
+	b := []byte(body)
+
+	if compress && len(b) >= min {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if acceptsGzip(r) {
+			var bf bytes.Buffer
+
+			z := gzip.NewWriter(&bf)
+			z.Write(b)
+			z.Close()
+
+			b = bf.Bytes()
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.WriteHeader(code)
+	w.Write(b)
+}
+
+// limitBody reads request body up to max bytes, so later reads are served from memory. Larger
+// bodies are responded 413 and unreadable ones 400, it returns false then.
+func limitBody(w http.ResponseWriter, r *http.Request, max int64) bool {
+	if max <= 0 {
+		max = DefaultMaxBodySize
+	}
+
+	if r.ContentLength > max {
+		writeMessage(w, http.StatusRequestEntityTooLarge)
+		return false
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+
+	b, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, max))
+	if err != nil {
+		if int64(len(b)) >= max {
+			writeMessage(w, http.StatusRequestEntityTooLarge)
+		} else {
+			writeMessage(w, http.StatusBadRequest)
+		}
+
+		return false
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+
+	return true
+}
+
+// writeMessage responds code with JSON body of its status text
+func writeMessage(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, `{"message":%q}`, http.StatusText(code))
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, v := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		z := strings.Split(v, ";")
+
+		if strings.TrimSpace(z[0]) != "gzip" {
+			continue
+		}
+
+		for _, p := range z[1:] {
+			if q := strings.SplitN(strings.TrimSpace(p), "=", 2); len(q) == 2 && q[0] == "q" {
+				if f, err := strconv.ParseFloat(q[1], 64); err == nil && f == 0 {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func requestSchema(m *mockRecord) string {
+	for _, t := range m.Transactions {
+		if t.RequestSchema != "" {
+			return t.RequestSchema
+		}
+	}
+
+	return ""
+}
+
+func validateRequest(m *mockRecord, r *http.Request) []schema.Error {
+	s := requestSchema(m)
+	if s == "" {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return []schema.Error{{Field: "(root)", Message: err.Error()}}
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	errs, err := schema.Validate([]byte(s), b)
+	if err != nil {
+		return []schema.Error{{Field: "(root)", Message: err.Error()}}
+	}
+
+	return errs
+}
+
+func invalidRequest(w http.ResponseWriter, errs []schema.Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Request body does not match schema",
+		"errors":  errs,
+	})
+}
+
+func expandParams(body string, params denco.Params, format string) string {
+	if format == "" {
+		format = DefaultParamPlaceholder
+	}
+
+	for _, p := range params {
+		v, err := url.PathUnescape(p.Value)
+		if err != nil {
+			v = p.Value
+		}
+
+		body = strings.Replace(body, fmt.Sprintf(format, p.Name), v, -1)
+	}
+
+	return body
+}
+
+// wait sleeps for d, it returns false when the request is cancelled before.
+func wait(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// reservedHeader reports whether response header h is computed by the mock instead of copied from blueprint
+func reservedHeader(h string) bool {
+	switch http.CanonicalHeaderKey(h) {
+	case "Content-Length", "Content-Encoding", "Transfer-Encoding", "Connection", "Vary", "X-Mock-Delay":
+		return true
+	}
+
+	return false
+}
+
+func mockDelay(hs []api.Header) time.Duration {
+	for _, h := range hs {
+		if strings.EqualFold(h.Key, "X-Mock-Delay") {
+			if d, err := time.ParseDuration(strings.TrimSpace(h.Value)); err == nil {
+				return d
+			}
+		}
+	}
+
+	return 0
+}
+
 func preferStatusCode(r *http.Request) string {
 	var c string
 
 	for _, v := range strings.Split(r.Header.Get("Prefer"), ",") {
-		if z := strings.SplitN(v, "=", 2); z[0] == "status" {
-			c = z[1]
+		if z := strings.SplitN(strings.TrimSpace(v), "=", 2); len(z) == 2 && z[0] == "status" {
+			c = strings.TrimSpace(z[1])
 		}
 	}
 
@@ -195,10 +718,99 @@ func preferStatusCode(r *http.Request) string {
 	return c
 }
 
+var queryExpression = regexp.MustCompile(`\{[?&]([^}]+)\}`)
+
+// queryExamples maps query variables of route URI template to example values of their
+// parameters, transition parameters take precedence over resource ones.
+func queryExamples(x api.Route) map[string]string {
+	var ps []api.Parameter
+
+	if x.Transition != nil {
+		ps = append(ps, x.Transition.Href.Parameters...)
+	}
+
+	if x.Resource != nil {
+		ps = append(ps, x.Resource.Href.Parameters...)
+	}
+
+	q := map[string]string{}
+
+	for _, m := range queryExpression.FindAllStringSubmatch(x.URL, -1) {
+		for _, k := range strings.Split(m[1], ",") {
+			k = strings.TrimSuffix(strings.SplitN(strings.TrimSpace(k), ":", 2)[0], "*")
+
+			for _, p := range ps {
+				if p.Key == k {
+					if p.Value != "" {
+						q[k] = p.Value
+					}
+
+					break
+				}
+			}
+		}
+	}
+
+	if len(q) == 0 {
+		return nil
+	}
+
+	return q
+}
+
+// matchQuery keeps transactions whose declared query parameters all match q, preferring
+// those matching the most. Without a match, transactions declaring no query are kept,
+// falling back to all of them.
+func matchQuery(ts []*MockTransaction, q url.Values) []*MockTransaction {
+	var xs, base []*MockTransaction
+	var n int
+
+	for _, t := range ts {
+		if len(t.Query) == 0 {
+			base = append(base, t)
+			continue
+		}
+
+		if !queryMatches(t.Query, q) {
+			continue
+		}
+
+		switch {
+		case len(t.Query) > n:
+			xs, n = []*MockTransaction{t}, len(t.Query)
+		case len(t.Query) == n:
+			xs = append(xs, t)
+		}
+	}
+
+	if len(xs) > 0 {
+		return xs
+	}
+
+	if len(base) > 0 {
+		return base
+	}
+
+	return ts
+}
+
+func queryMatches(m map[string]string, q url.Values) bool {
+	for k, v := range m {
+		if q.Get(k) != v {
+			return false
+		}
+	}
+
+	return true
+}
+
 func transformURL(u, h string) string {
 	paramPattern := regexp.MustCompile(`\{\?[\w,]+\}`)
 	queryPattern := regexp.MustCompile(`\{([\w,]+)\}`)
+	reservedPattern := regexp.MustCompile(`\{\+(\w+)\}`)
 
+	// Reserved expansion may contain slashes, it catches the remaining segments
+	u = reservedPattern.ReplaceAllString(u, "*${1}")
 	u = queryPattern.ReplaceAllString(u, ":${1}")
 	u = paramPattern.ReplaceAllLiteralString(u, "")
 	u = strings.Replace(u, h, "", 1)
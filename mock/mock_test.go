@@ -0,0 +1,1044 @@
+package mock_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const userSchema = `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "type": "object",
+  "properties": {
+    "name": {"type": "string"},
+    "age": {"type": "number"}
+  },
+  "required": ["name"]
+}`
+
+func newAPI() *api.API {
+	return &api.API{
+		Metadata: []api.Metadata{{Key: "HOST", Value: "https://api.example.com"}},
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Transitions: []*api.Transition{
+							{
+								URL: "https://api.example.com/users",
+								Transactions: []api.Transaction{
+									{
+										Request: api.Request{
+											Method: "POST",
+											Schema: api.Asset{ContentType: "application/schema+json", Body: userSchema},
+										},
+										Response: api.Response{
+											StatusCode: 201,
+											Body:       api.Asset{ContentType: "application/json", Body: `{"id": 1}`},
+										},
+									},
+								},
+							},
+							{
+								URL: "https://api.example.com/users",
+								Transactions: []api.Transaction{
+									{
+										Request: api.Request{Method: "GET"},
+										Response: api.Response{
+											StatusCode: 200,
+											Body:       api.Asset{ContentType: "application/json", Body: `[]`},
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Transitions: []*api.Transition{
+							{
+								URL: "https://api.example.com/users/{id}",
+								Transactions: []api.Transaction{
+									{
+										Request:  api.Request{Method: "GET"},
+										Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/json", Body: `{"id": 1}`}},
+									},
+									{
+										Request:  api.Request{Method: "GET"},
+										Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/json", Body: `{"id": 2}`}},
+									},
+									{
+										Request:  api.Request{Method: "GET"},
+										Response: api.Response{StatusCode: 404, Body: api.Asset{ContentType: "application/json", Body: `{"message": "Not Found"}`}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func serve(h http.Handler, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestMockHandler(t *testing.T) {
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{})
+
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `[]`, w.Body.String())
+
+	w = serve(h, "POST", "/users", `{}`, nil)
+	assert.Equal(t, 201, w.Code)
+
+	w = serve(h, "GET", "/unknown", "", nil)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestMockHandler_strictRequest(t *testing.T) {
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{StrictRequest: true})
+
+	w := serve(h, "POST", "/users", `{"name": "olaf", "age": 20}`, nil)
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, `{"id": 1}`, w.Body.String())
+
+	w = serve(h, "POST", "/users", `{"age": "20"}`, nil)
+	assert.Equal(t, 422, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"message": "Request body does not match schema",
+		"errors": [
+			{"field": "name", "message": "is required"},
+			{"field": "age", "message": "must be of type number"}
+		]
+	}`, w.Body.String())
+
+	w = serve(h, "POST", "/users", `{"name": `, nil)
+	assert.Equal(t, 422, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid JSON")
+
+	w = serve(h, "GET", "/users", `not json`, nil)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestMockHandler_prefer(t *testing.T) {
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{})
+
+	w := serve(h, "GET", "/users/1", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id": 1}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"Prefer": "status=404"})
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, `{"message": "Not Found"}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"Prefer": "respond-async, status=404"})
+	assert.Equal(t, 404, w.Code)
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"X-Status-Code": "404"})
+	assert.Equal(t, 404, w.Code)
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"Prefer": "status=200", "X-Status-Code": "404"})
+	assert.Equal(t, 200, w.Code)
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"Prefer": "status=500"})
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id": 1}`, w.Body.String())
+}
+
+func TestMockHandler_multi(t *testing.T) {
+	b := &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Transitions: []*api.Transition{
+							{
+								URL: "/health",
+								Transactions: []api.Transaction{
+									{Request: api.Request{Method: "GET"}, Response: api.Response{StatusCode: 204}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI(), b}), mock.Options{})
+
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+
+	w = serve(h, "GET", "/health", "", nil)
+	assert.Equal(t, 204, w.Code)
+}
+
+func TestMockHandler_delay(t *testing.T) {
+	b := newAPI()
+	x := &b.ResourceGroups[0].Resources[1].Transitions[0].Transactions[2]
+	x.Response.Headers = []api.Header{{Key: "X-Mock-Delay", Value: "50ms"}}
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{Delay: 20 * time.Millisecond})
+
+	n := time.Now()
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.True(t, time.Since(n) >= 20*time.Millisecond)
+
+	n = time.Now()
+	w = serve(h, "GET", "/users/1", "", map[string]string{"Prefer": "status=404"})
+	assert.Equal(t, 404, w.Code)
+	assert.True(t, time.Since(n) >= 50*time.Millisecond)
+}
+
+func TestMockHandler_delayCancelled(t *testing.T) {
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{Delay: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := httptest.NewRequest("GET", "/users", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	n := time.Now()
+	h.ServeHTTP(w, r)
+	assert.True(t, time.Since(n) < time.Second)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestMockHandler_params(t *testing.T) {
+	b := newAPI()
+	b.ResourceGroups[0].Resources[1].Transitions = append(b.ResourceGroups[0].Resources[1].Transitions, &api.Transition{
+		URL: "https://api.example.com/users/{id}/posts/{post_id}",
+		Transactions: []api.Transaction{
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/json", Body: `{"user": "{id}", "id": "{post_id}"}`}},
+			},
+		},
+	})
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w := serve(h, "GET", "/users/olaf/posts/hello%20world", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"user": "olaf", "id": "hello world"}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/a%2Fb/posts/1", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"user": "a/b", "id": "1"}`, w.Body.String())
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{ParamPlaceholder: ":%s"})
+
+	w = serve(h, "GET", "/users/olaf/posts/1", "", nil)
+	assert.Equal(t, `{"user": "{id}", "id": "{post_id}"}`, w.Body.String())
+}
+
+func TestMockHandler_catchAll(t *testing.T) {
+	b := newAPI()
+	b.ResourceGroups[0].Resources[1].Transitions = append(b.ResourceGroups[0].Resources[1].Transitions, &api.Transition{
+		URL: "https://api.example.com/files/{+path}{?download}",
+		Transactions: []api.Transaction{
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/json", Body: `{"path": "{path}"}`}},
+			},
+		},
+	})
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w := serve(h, "GET", "/files/a.txt", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"path": "a.txt"}`, w.Body.String())
+
+	w = serve(h, "GET", "/files/a/b/c?download=1", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"path": "a/b/c"}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/1/extra", "", nil)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestMockHandler_query(t *testing.T) {
+	search := func(u, body string, ps ...api.Parameter) *api.Transition {
+		return &api.Transition{
+			URL:  "https://api.example.com" + u,
+			Href: api.Href{Parameters: ps},
+			Transactions: []api.Transaction{
+				{
+					Request:  api.Request{Method: "GET"},
+					Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/json", Body: body}},
+				},
+			},
+		}
+	}
+
+	b := newAPI()
+	b.ResourceGroups[0].Resources = append(b.ResourceGroups[0].Resources, &api.Resource{
+		Href: api.Href{Parameters: []api.Parameter{{Key: "size", Value: "hd"}}},
+		Transitions: []*api.Transition{
+			search("/search", `"all"`),
+			search("/search{?type}", `"image"`, api.Parameter{Key: "type", Value: "image"}),
+			search("/search{?type}", `"video"`, api.Parameter{Key: "type", Value: "video"}),
+			search("/search{?type,size}", `"video hd"`, api.Parameter{Key: "type", Value: "video"}),
+			search("/search{?page}", `"page"`, api.Parameter{Key: "page"}),
+		},
+	})
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	for target, body := range map[string]string{
+		"/search?type=image":         `"image"`,
+		"/search?type=video":         `"video"`,
+		"/search?size=hd&type=video": `"video hd"`,
+		"/search?type=video&size=sd": `"video"`,
+		"/search?type=audio":         `"all"`,
+		"/search?size=hd":            `"all"`,
+		"/search":                    `"all"`,
+	} {
+		w := serve(h, "GET", target, "", nil)
+		assert.Equal(t, 200, w.Code, target)
+		assert.Equal(t, body, w.Body.String(), target)
+	}
+}
+
+func TestMockHandler_headers(t *testing.T) {
+	b := newAPI()
+	b.ResourceGroups[0].Resources[0].Transitions[1].Transactions[0].Response.Headers = []api.Header{
+		{Key: "Content-Type", Value: "application/json"},
+		{Key: "Cache-Control", Value: "max-age=60"},
+		{Key: "X-RateLimit-Remaining", Value: "99"},
+		{Key: "Content-Length", Value: "1000"},
+		{Key: "X-Mock-Delay", Value: "1ms"},
+	}
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "max-age=60", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "99", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "2", w.Header().Get("Content-Length"))
+	assert.Empty(t, w.Header().Get("X-Mock-Delay"))
+}
+
+func TestDuplicates(t *testing.T) {
+	a := newAPI()
+	b := newAPI()
+	b.ResourceGroups[0].Resources = b.ResourceGroups[0].Resources[1:]
+	b.ResourceGroups[0].Resources[0].Transitions[0].URL = "https://api.example.com/users/{user_id}"
+
+	ds := mock.Duplicates(mock.MockMulti([]*api.API{a, b, newAPI()}))
+	assert.Equal(t, []mock.Duplicate{
+		{Method: "GET", Path: "/users", Sources: []int{0, 2}},
+		{Method: "POST", Path: "/users", Sources: []int{0, 2}},
+		{Method: "GET", Path: "/users/:id", Sources: []int{0, 1, 2}},
+	}, ds)
+
+	assert.Empty(t, mock.Duplicates(mock.MockMulti([]*api.API{a})))
+}
+
+func TestMockHandler_example(t *testing.T) {
+	b := newAPI()
+	ts := b.ResourceGroups[0].Resources[1].Transitions[0].Transactions
+	ts[0].Request.Title = "Empty"
+	ts[1].Request.Title = "Full"
+	ts[2].Request.Title = "Full"
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w := serve(h, "GET", "/users/1", "", map[string]string{"X-Mock-Example": "full"})
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id": 2}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"X-Mock-Example": "Full", "Prefer": "status=404"})
+	assert.Equal(t, 404, w.Code)
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"X-Mock-Example": "Empty", "Prefer": "status=404"})
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id": 1}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"X-Mock-Example": "Unknown"})
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id": 1}`, w.Body.String())
+}
+
+func TestWithBasePath(t *testing.T) {
+	assert.Equal(t, "/api/v2/users", mock.JoinBasePath("/api/v2", "/users"))
+	assert.Equal(t, "/api/v2/users", mock.JoinBasePath("api/v2/", "users"))
+	assert.Equal(t, "/api/v2", mock.JoinBasePath("/api/v2/", "/"))
+
+	ms := mock.MockMulti([]*api.API{newAPI()})
+	xs := mock.WithBasePath(ms, "/api/v2/")
+	assert.Equal(t, "/users", ms[0][0].Pattern)
+	assert.Equal(t, "/api/v2/users", xs[0][0].Pattern)
+
+	h := mock.MockHandler(xs, mock.Options{})
+
+	w := serve(h, "GET", "/api/v2/users/1", "", nil)
+	assert.Equal(t, 200, w.Code)
+
+	w = serve(h, "GET", "/users/1", "", nil)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestMockHandler_notFound(t *testing.T) {
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{})
+
+	w := serve(h, "GET", "/user/1", "", nil)
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"message": "Not Found", "suggestions": ["GET /users", "POST /users", "GET /users/:id"]}`, w.Body.String())
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{NotFoundBody: `{"error": "nope"}`})
+
+	w = serve(h, "GET", "/user/1", "", nil)
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, `{"error": "nope"}`, w.Body.String())
+}
+
+func TestMockHandler_admin(t *testing.T) {
+	b := newAPI()
+	b.ResourceGroups[0].Resources[0].Transitions[1].URL = "https://api.example.com/__health"
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{AdminPrefix: "/_mock/"})
+
+	w := serve(h, "GET", "/_mock/health", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+
+	w = serve(h, "GET", "/_mock/routes", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `[
+		{"method": "POST", "path": "/users", "statusCode": 201},
+		{"method": "GET", "path": "/__health", "statusCode": 200},
+		{"method": "GET", "path": "/users/:id", "statusCode": 200},
+		{"method": "GET", "path": "/users/:id", "statusCode": 200},
+		{"method": "GET", "path": "/users/:id", "statusCode": 404}
+	]`, w.Body.String())
+
+	w = serve(h, "GET", "/__health", "", nil)
+	assert.Equal(t, `[]`, w.Body.String())
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{})
+
+	w = serve(h, "GET", "/_mock/health", "", nil)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestMockHandler_proxy(t *testing.T) {
+	n := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, `{"path": "`+r.URL.RequestURI()+`"}`)
+	}))
+
+	u, _ := url.Parse(upstream.URL)
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	f := filepath.Join(dir, "cassette.json")
+
+	c, err := mock.LoadCassette(f)
+	assert.Nil(t, err)
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{Proxy: u, Cassette: c})
+
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, 0, n)
+
+	w = serve(h, "GET", "/orders?page=2", "", nil)
+	assert.Equal(t, 202, w.Code)
+	assert.Equal(t, `{"path": "/orders?page=2"}`, w.Body.String())
+	assert.Equal(t, 1, n)
+
+	upstream.Close()
+
+	c, err = mock.LoadCassette(f)
+	assert.Nil(t, err)
+	assert.Len(t, c.Entries, 1)
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{Cassette: c, Replay: true})
+
+	w = serve(h, "GET", "/orders?page=2", "", nil)
+	assert.Equal(t, 202, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"path": "/orders?page=2"}`, w.Body.String())
+
+	w = serve(h, "GET", "/orders", "", nil)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestMockHandler_proxyTimeout(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Slow") != "" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+
+		io.WriteString(w, `"upstream"`)
+	}))
+	defer upstream.Close()
+
+	u, _ := url.Parse(upstream.URL)
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{Proxy: u, UpstreamTimeout: 20 * time.Millisecond})
+
+	w := serve(h, "GET", "/orders", "", map[string]string{"X-Slow": "1"})
+	assert.Equal(t, 504, w.Code)
+
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := mock.LoadCassette(filepath.Join(dir, "cassette.json"))
+	assert.Nil(t, err)
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{Proxy: u, Cassette: c, UpstreamTimeout: time.Second, UpstreamFallback: true})
+
+	w = serve(h, "GET", "/orders", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"upstream"`, w.Body.String())
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{Proxy: u, Cassette: c, UpstreamTimeout: 20 * time.Millisecond, UpstreamFallback: true})
+
+	slow := map[string]string{"X-Slow": "1"}
+
+	w = serve(h, "GET", "/orders", "", slow)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"upstream"`, w.Body.String())
+
+	w = serve(h, "GET", "/payments", "", slow)
+	assert.Equal(t, 504, w.Code)
+}
+
+func TestMockHandler_proxyFallback(t *testing.T) {
+	posted := make(chan string, 1)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			b, _ := ioutil.ReadAll(r.Body)
+			posted <- string(b)
+		}
+
+		if r.Header.Get("X-Slow") != "" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+
+		io.WriteString(w, `"upstream"`)
+	}))
+	defer upstream.Close()
+
+	u, _ := url.Parse(upstream.URL)
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{Proxy: u, UpstreamTimeout: 20 * time.Millisecond, UpstreamFallback: true})
+
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"upstream"`, w.Body.String())
+
+	slow := map[string]string{"X-Slow": "1"}
+
+	w = serve(h, "GET", "/users", "", slow)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `[]`, w.Body.String())
+
+	w = serve(h, "POST", "/users", `{"name": "olaf"}`, slow)
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, `{"name": "olaf"}`, <-posted)
+
+	w = serve(h, "GET", "/payments", "", slow)
+	assert.Equal(t, 504, w.Code)
+}
+
+func TestMockHandler_accept(t *testing.T) {
+	b := newAPI()
+	b.ResourceGroups[0].Resources[1].Transitions = append(b.ResourceGroups[0].Resources[1].Transitions, &api.Transition{
+		URL: "https://api.example.com/notes",
+		Transactions: []api.Transaction{
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/json", Body: `[]`}},
+			},
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/xml", Body: `<notes/>`}},
+			},
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 404, Body: api.Asset{ContentType: "application/json", Body: `{}`}},
+			},
+		},
+	})
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	cases := []struct {
+		accept      string
+		prefer      string
+		code        int
+		contentType string
+	}{
+		{"", "", 200, "application/json"},
+		{"application/xml", "", 200, "application/xml"},
+		{"application/json;q=0.5, application/xml", "", 200, "application/xml"},
+		{"application/json, application/xml;q=0.9", "", 200, "application/json"},
+		{"application/*", "", 200, "application/json"},
+		{"*/*", "", 200, "application/json"},
+		{"application/*;q=0.2, application/xml;q=0.8", "", 200, "application/xml"},
+		{"application/xml;q=0, */*", "", 200, "application/json"},
+		{"text/html", "", 406, ""},
+		{"application/xml", "status=404", 406, ""},
+		{"application/*", "status=404", 404, "application/json"},
+	}
+
+	for _, c := range cases {
+		w := serve(h, "GET", "/notes", "", map[string]string{"Accept": c.accept, "Prefer": c.prefer})
+		assert.Equal(t, c.code, w.Code, c.accept)
+		assert.Equal(t, c.contentType, w.Header().Get("Content-Type"), c.accept)
+	}
+}
+
+func TestMockHandler_gzip(t *testing.T) {
+	b := newAPI()
+	x := &b.ResourceGroups[0].Resources[0].Transitions[1].Transactions[0]
+	x.Response.Body.Body = `[` + strings.Repeat(`{"name": "olaf"},`, 100) + `{}]`
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w := serve(h, "GET", "/users", "", map[string]string{"Accept-Encoding": "deflate, gzip"})
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"))
+
+	z, err := gzip.NewReader(w.Body)
+	assert.Nil(t, err)
+
+	s, err := ioutil.ReadAll(z)
+	assert.Nil(t, err)
+	assert.Equal(t, x.Response.Body.Body, string(s))
+
+	w = serve(h, "GET", "/users", "", map[string]string{"Accept-Encoding": "gzip;q=0"})
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, strconv.Itoa(len(x.Response.Body.Body)), w.Header().Get("Content-Length"))
+	assert.Equal(t, x.Response.Body.Body, w.Body.String())
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"Accept-Encoding": "gzip"})
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "9", w.Header().Get("Content-Length"))
+	assert.Equal(t, `{"id": 1}`, w.Body.String())
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{GzipMinLength: 5})
+
+	w = serve(h, "GET", "/users/1", "", map[string]string{"Accept-Encoding": "gzip"})
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"))
+}
+
+func TestMockHandler_cycleExamples(t *testing.T) {
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{CycleExamples: true})
+
+	for _, s := range []string{`{"id": 1}`, `{"id": 2}`, `{"id": 1}`} {
+		w := serve(h, "GET", "/users/1", "", nil)
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, s, w.Body.String())
+	}
+
+	w := serve(h, "GET", "/users/1", "", map[string]string{"Prefer": "status=404"})
+	assert.Equal(t, 404, w.Code)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			serve(h, "GET", "/users/1", "", nil)
+		}()
+	}
+
+	wg.Wait()
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{})
+
+	for i := 0; i < 2; i++ {
+		w := serve(h, "GET", "/users/1", "", nil)
+		assert.Equal(t, `{"id": 1}`, w.Body.String())
+	}
+}
+
+func TestReloader(t *testing.T) {
+	h := mock.NewReloader(mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{}))
+
+	w := serve(h, "GET", "/health", "", nil)
+	assert.Equal(t, 404, w.Code)
+
+	b := &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Transitions: []*api.Transition{
+							{
+								URL: "/health",
+								Transactions: []api.Transaction{
+									{Request: api.Request{Method: "GET"}, Response: api.Response{StatusCode: 204}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	h.Swap(mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{}))
+
+	w = serve(h, "GET", "/health", "", nil)
+	assert.Equal(t, 204, w.Code)
+
+	w = serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestMockHandler_methodNotAllowed(t *testing.T) {
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{})
+
+	w := serve(h, "DELETE", "/users", "", nil)
+	assert.Equal(t, 405, w.Code)
+	assert.Equal(t, "GET, POST", w.Header().Get("Allow"))
+
+	w = serve(h, "POST", "/users/1", "", nil)
+	assert.Equal(t, 405, w.Code)
+	assert.Equal(t, "GET", w.Header().Get("Allow"))
+
+	w = serve(h, "DELETE", "/unknown", "", nil)
+	assert.Equal(t, 404, w.Code)
+	assert.Empty(t, w.Header().Get("Allow"))
+}
+
+func TestMockHandler_dynamic(t *testing.T) {
+	b := newAPI()
+	x := &b.ResourceGroups[0].Resources[0].Transitions[1].Transactions[0]
+	x.Response.Body.Body = `{"id": "{{faker.uuid}}", "name": "{{ faker.name }}", "email": "{{faker.email}}", "age": {{faker.number}}, "at": "{{faker.now}}", "x": "{{faker.unknown}}"}`
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{Dynamic: true})
+
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Regexp(t, `^\{"id": "[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}", "name": "\w+ \w+", "email": "\w+\.\w+@example\.com", "age": \d+, "at": "\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ", "x": "\{\{faker\.unknown\}\}"\}$`, w.Body.String())
+
+	z := serve(h, "GET", "/users", "", nil)
+	assert.NotEqual(t, w.Body.String(), z.Body.String())
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w = serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, x.Response.Body.Body, w.Body.String())
+}
+
+func TestCheckFakers(t *testing.T) {
+	b := newAPI()
+	x := &b.ResourceGroups[0].Resources[0].Transitions[1].Transactions[0]
+	x.Response.Body.Body = `{"id": "{{faker.uuid}}"}`
+
+	assert.Nil(t, mock.CheckFakers(mock.MockMulti([]*api.API{b})))
+
+	x.Response.Body.Body = `{"id": "{{faker.uuid}}", "city": "{{ faker.city }}"}`
+
+	err := mock.CheckFakers(mock.MockMulti([]*api.API{b}))
+	assert.EqualError(t, err, "GET /users: unknown faker directive: {{ faker.city }}")
+}
+
+func TestMockHandler_stateful(t *testing.T) {
+	b := newAPI()
+	b.ResourceGroups[0].Resources[1].Transitions = append(b.ResourceGroups[0].Resources[1].Transitions, &api.Transition{
+		URL: "https://api.example.com/users/{id}",
+		Transactions: []api.Transaction{
+			{
+				Request:  api.Request{Method: "DELETE"},
+				Response: api.Response{StatusCode: 204},
+			},
+		},
+	})
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{StrictRequest: true, State: mock.NewState()})
+
+	w := serve(h, "POST", "/users", `{"name": "olaf", "age": 20}`, nil)
+	assert.Equal(t, 201, w.Code)
+	assert.JSONEq(t, `{"id": 1, "name": "olaf", "age": 20}`, w.Body.String())
+
+	w = serve(h, "POST", "/users", `{"id": "x", "name": "elsa", "age": 21}`, nil)
+	assert.Equal(t, 201, w.Code)
+	assert.JSONEq(t, `{"id": "x", "name": "elsa", "age": 21}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/1", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id": 1, "name": "olaf", "age": 20}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/x", "", nil)
+	assert.JSONEq(t, `{"id": "x", "name": "elsa", "age": 21}`, w.Body.String())
+
+	w = serve(h, "GET", "/users/2", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id": 1}`, w.Body.String())
+
+	w = serve(h, "DELETE", "/users/1", "", nil)
+	assert.Equal(t, 204, w.Code)
+
+	w = serve(h, "GET", "/users/1", "", nil)
+	assert.Equal(t, 404, w.Code)
+
+	w = serve(h, "POST", "/users", `{"name": "anna", "age": 18}`, nil)
+	assert.JSONEq(t, `{"id": 2, "name": "anna", "age": 18}`, w.Body.String())
+
+	w = serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, `[]`, w.Body.String())
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w = serve(h, "POST", "/users", `{"name": "olaf", "age": 20}`, nil)
+	assert.Equal(t, `{"id": 1}`, w.Body.String())
+}
+
+func TestMockHandler_mediaTypes(t *testing.T) {
+	b := newAPI()
+	b.ResourceGroups[0].Resources[1].Transitions = append(b.ResourceGroups[0].Resources[1].Transitions, &api.Transition{
+		URL: "https://api.example.com/notes",
+		Transactions: []api.Transaction{
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/vnd.company.v2+json", Body: strings.Repeat(" ", 2000) + `[]`}},
+			},
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/vnd.company.v1", Body: strings.Repeat(" ", 2000) + `{}`}},
+			},
+		},
+	})
+
+	mt, err := mock.ParseMediaTypes([]string{"application/vnd.company.v1=application/json"})
+	assert.Nil(t, err)
+	assert.Equal(t, "application/json", mt.Base("application/vnd.company.v1; charset=utf-8"))
+	assert.Equal(t, "application/json", mt.Base("application/problem+json"))
+	assert.Equal(t, "application/xml", mt.Base("application/atom+xml"))
+	assert.Equal(t, "image/png", mt.Base("image/png"))
+
+	_, err = mock.ParseMediaTypes([]string{"application/vnd.company.v1"})
+	assert.NotNil(t, err)
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{StrictRequest: true, MediaTypes: mt})
+
+	w := serve(h, "GET", "/notes", "", map[string]string{"Accept": "application/json", "Accept-Encoding": "gzip"})
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/vnd.company.v2+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	w = serve(h, "GET", "/notes", "", map[string]string{"Accept": "application/vnd.company.v1, application/json;q=0.5", "Accept-Encoding": "gzip"})
+	assert.Equal(t, "application/vnd.company.v1", w.Header().Get("Content-Type"))
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	w = serve(h, "GET", "/notes", "", map[string]string{"Accept": "text/html"})
+	assert.Equal(t, 406, w.Code)
+
+	w = serve(h, "POST", "/users", `{"name": "olaf", "age": 20}`, map[string]string{"Content-Type": "application/vnd.company.v1"})
+	assert.Equal(t, 201, w.Code)
+
+	w = serve(h, "POST", "/users", `{"age": "20"}`, map[string]string{"Content-Type": "application/vnd.company.v2+json"})
+	assert.Equal(t, 422, w.Code)
+
+	w = serve(h, "POST", "/users", `name=olaf`, map[string]string{"Content-Type": "application/x-www-form-urlencoded"})
+	assert.Equal(t, 415, w.Code)
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w = serve(h, "GET", "/notes", "", map[string]string{"Accept": "application/vnd.company.v1", "Accept-Encoding": "gzip"})
+	assert.Equal(t, "application/vnd.company.v1", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+}
+
+func TestMockHandler_rateLimit(t *testing.T) {
+	l, err := mock.ParseRateLimit("2/200ms")
+	assert.Nil(t, err)
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{RateLimit: l})
+
+	for i := 0; i < 2; i++ {
+		w := serve(h, "GET", "/users", "", nil)
+		assert.Equal(t, 200, w.Code)
+	}
+
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 429, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"message":"Too Many Requests"}`, w.Body.String())
+
+	r := httptest.NewRequest("GET", "/users", nil)
+	r.RemoteAddr = "198.51.100.7:4321"
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	time.Sleep(250 * time.Millisecond)
+
+	w = serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestParseRateLimit(t *testing.T) {
+	l, err := mock.ParseRateLimit("5/s")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, l.Limit)
+	assert.Equal(t, time.Second, l.Window)
+
+	l, err = mock.ParseRateLimit("10/1m")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, l.Limit)
+	assert.Equal(t, time.Minute, l.Window)
+
+	l, err = mock.ParseRateLimit("")
+	assert.Nil(t, err)
+	assert.Nil(t, l)
+
+	for _, s := range []string{"10", "x/1m", "0/1m", "10/x", "10/-1s"} {
+		_, err = mock.ParseRateLimit(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestMockHandler_basicAuth(t *testing.T) {
+	a, err := mock.ParseBasicAuth("olaf:snow:man")
+	assert.Nil(t, err)
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{BasicAuth: a, AdminPrefix: "/__"})
+
+	w := serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, `Basic realm="snowboard", charset="UTF-8"`, w.Header().Get("WWW-Authenticate"))
+	assert.JSONEq(t, `{"message":"Unauthorized"}`, w.Body.String())
+
+	r := httptest.NewRequest("GET", "/users", nil)
+	r.SetBasicAuth("olaf", "wrong")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, 401, w.Code)
+
+	r = httptest.NewRequest("GET", "/users", nil)
+	r.SetBasicAuth("olaf", "snow:man")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	w = serve(h, "GET", "/__health", "", nil)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestParseBasicAuth(t *testing.T) {
+	a, err := mock.ParseBasicAuth("olaf:secret")
+	assert.Nil(t, err)
+	assert.Equal(t, &mock.BasicAuth{Username: "olaf", Password: "secret"}, a)
+
+	a, err = mock.ParseBasicAuth("")
+	assert.Nil(t, err)
+	assert.Nil(t, a)
+
+	for _, s := range []string{"olaf", ":secret"} {
+		_, err = mock.ParseBasicAuth(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestMockHandler_xml(t *testing.T) {
+	b := newAPI()
+	b.ResourceGroups[0].Resources[1].Transitions = append(b.ResourceGroups[0].Resources[1].Transitions, &api.Transition{
+		URL: "https://api.example.com/orders",
+		Transactions: []api.Transaction{
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/json", Body: `[]`}},
+			},
+			{
+				Request:  api.Request{Method: "GET"},
+				Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "text/xml", Body: `<orders><order id="1"/></orders>`}},
+			},
+		},
+	})
+
+	h := mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{IndentXML: true})
+
+	w := serve(h, "GET", "/orders", "", map[string]string{"Accept": "application/xml"})
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "text/xml", w.Header().Get("Content-Type"))
+	assert.Equal(t, "<orders>\n  <order id=\"1\"/>\n</orders>", w.Body.String())
+
+	w = serve(h, "GET", "/orders", "", nil)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	h = mock.MockHandler(mock.MockMulti([]*api.API{b}), mock.Options{})
+
+	w = serve(h, "GET", "/orders", "", map[string]string{"Accept": "application/xml"})
+	assert.Equal(t, `<orders><order id="1"/></orders>`, w.Body.String())
+}
+
+func TestMockHandler_maxBodySize(t *testing.T) {
+	h := mock.MockHandler(mock.MockMulti([]*api.API{newAPI()}), mock.Options{StrictRequest: true, MaxBodySize: 32})
+
+	w := serve(h, "POST", "/users", `{"name": "olaf", "age": 20}`, nil)
+	assert.Equal(t, 201, w.Code)
+
+	w = serve(h, "POST", "/users", `{"name": "`+strings.Repeat("o", 32)+`", "age": 20}`, nil)
+	assert.Equal(t, 413, w.Code)
+	assert.JSONEq(t, `{"message":"Request Entity Too Large"}`, w.Body.String())
+
+	r := httptest.NewRequest("POST", "/users", ioutil.NopCloser(strings.NewReader(strings.Repeat(" ", 64))))
+	r.ContentLength = -1
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, 413, w.Code)
+
+	w = serve(h, "GET", "/users", "", nil)
+	assert.Equal(t, 200, w.Code)
+}
@@ -0,0 +1,88 @@
+package mock
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+type mediaRange struct {
+	Type    string
+	Subtype string
+	Quality float64
+}
+
+func parseAccept(s string) []mediaRange {
+	var xs []mediaRange
+
+	for _, v := range strings.Split(s, ",") {
+		t, params, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+
+		z := strings.SplitN(t, "/", 2)
+		if len(z) != 2 {
+			continue
+		}
+
+		m := mediaRange{Type: z[0], Subtype: z[1], Quality: 1}
+
+		if q, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(q, 64); err == nil {
+				m.Quality = f
+			}
+		}
+
+		xs = append(xs, m)
+	}
+
+	return xs
+}
+
+// acceptQuality returns q-value of the most specific media range matching content type, or
+// its base type in mt, e.g. `application/json` for `application/vnd.company.v2+json`.
+// Responses without content type are acceptable to any request.
+func acceptQuality(rs []mediaRange, mt MediaTypes, contentType string) float64 {
+	if contentType == "" {
+		return 1
+	}
+
+	t, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return 0
+	}
+
+	z := strings.SplitN(t, "/", 2)
+	if len(z) != 2 {
+		return 0
+	}
+
+	b := strings.SplitN(mt.Base(t), "/", 2)
+	if len(b) != 2 {
+		b = z
+	}
+
+	q, n := 0.0, 0
+
+	for _, r := range rs {
+		s := 0
+
+		switch {
+		case r.Type == z[0] && r.Subtype == z[1]:
+			s = 4
+		case r.Type == b[0] && r.Subtype == b[1]:
+			s = 3
+		case (r.Type == z[0] || r.Type == b[0]) && r.Subtype == "*":
+			s = 2
+		case r.Type == "*" && r.Subtype == "*":
+			s = 1
+		}
+
+		if s > n {
+			q, n = r.Quality, s
+		}
+	}
+
+	return q
+}
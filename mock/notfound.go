@@ -0,0 +1,124 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// maxSuggestions limits routes suggested in not found response
+const maxSuggestions = 3
+
+type route struct {
+	Method string
+	Path   string
+}
+
+type notFoundBody struct {
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// knownRoutes lists unique routes of ms
+func knownRoutes(ms []MockTransactions) []route {
+	seen := map[route]bool{}
+	var rs []route
+
+	for _, mm := range ms {
+		for _, m := range mm {
+			r := route{Method: m.Method, Path: m.Path}
+
+			if !seen[r] {
+				seen[r] = true
+				rs = append(rs, r)
+			}
+		}
+	}
+
+	return rs
+}
+
+// suggestRoutes picks routes closest to path p by edit distance, ties are ordered by path then method
+func suggestRoutes(rs []route, p string) []string {
+	type candidate struct {
+		route
+		distance int
+	}
+
+	cs := make([]candidate, len(rs))
+	for i, r := range rs {
+		cs[i] = candidate{r, levenshtein(p, r.Path)}
+	}
+
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].distance != cs[j].distance {
+			return cs[i].distance < cs[j].distance
+		}
+
+		if cs[i].Path != cs[j].Path {
+			return cs[i].Path < cs[j].Path
+		}
+
+		return cs[i].Method < cs[j].Method
+	})
+
+	xs := []string{}
+
+	for i := 0; i < len(cs) && i < maxSuggestions; i++ {
+		xs = append(xs, cs[i].Method+" "+cs[i].Path)
+	}
+
+	return xs
+}
+
+// notFound responds 404 with custom body, or JSON listing routes closest to the requested path
+func notFound(w http.ResponseWriter, r *http.Request, rs []route, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+
+	if body != "" {
+		w.Write([]byte(body))
+		return
+	}
+
+	json.NewEncoder(w).Encode(notFoundBody{
+		Message:     "Not Found",
+		Suggestions: suggestRoutes(rs, r.URL.Path),
+	})
+}
+
+func levenshtein(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	d := make([]int, len(y)+1)
+
+	for j := range d {
+		d[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		prev := d[0]
+		d[0] = i
+
+		for j := 1; j <= len(y); j++ {
+			cur := d[j]
+			cost := 1
+
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			d[j] = min(min(d[j]+1, d[j-1]+1), prev+cost)
+			prev = cur
+		}
+	}
+
+	return d[len(y)]
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,222 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Cassette stores upstream responses captured by proxy mode
+type Cassette struct {
+	name    string
+	mu      sync.Mutex
+	Entries []*CassetteEntry `json:"entries"`
+}
+
+// CassetteEntry is a single captured response
+type CassetteEntry struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// LoadCassette reads cassette file, a missing file yields an empty cassette
+func LoadCassette(name string) (*Cassette, error) {
+	c := &Cassette{name: name}
+
+	b, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Find returns captured response for the request, if any
+func (c *Cassette) Find(r *http.Request) *CassetteEntry {
+	return c.find(r.Method, r.URL.RequestURI())
+}
+
+func (c *Cassette) find(method, uri string) *CassetteEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.Entries {
+		if e.Method == method && e.URL == uri {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Record stores the entry, replacing previous capture of the same request, and saves the cassette file
+func (c *Cassette) Record(e *CassetteEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	found := false
+
+	for i := range c.Entries {
+		if c.Entries[i].Method == e.Method && c.Entries[i].URL == e.URL {
+			c.Entries[i] = e
+			found = true
+		}
+	}
+
+	if !found {
+		c.Entries = append(c.Entries, e)
+	}
+
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.name, b, 0644)
+}
+
+func (e *CassetteEntry) serve(w http.ResponseWriter) {
+	for k, vs := range e.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+
+	w.WriteHeader(e.StatusCode)
+	io.WriteString(w, e.Body)
+}
+
+type requestURIKey struct{}
+
+type requestKey struct{}
+
+// proxy forwards the request to upstream, keeping the original request URI for cassette lookups
+// and the original request, with its body rewound, for fallback. Upstream taking longer than a
+// positive timeout is cancelled.
+func proxy(p http.Handler, w http.ResponseWriter, r *http.Request, timeout time.Duration) {
+	ctx := context.WithValue(r.Context(), requestURIKey{}, r.URL.RequestURI())
+	ctx = context.WithValue(ctx, requestKey{}, rewind(r))
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	p.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// rewind returns a copy of r reading its body from the start, when the body is buffered
+func rewind(r *http.Request) *http.Request {
+	x := *r
+
+	if r.GetBody != nil {
+		if b, err := r.GetBody(); err == nil {
+			x.Body = b
+		}
+	}
+
+	return &x
+}
+
+// newProxy forwards requests to u, capturing responses into c. Failed requests are answered with
+// 504 on timeout, 502 otherwise. When fallback is set, the original request is passed to it first,
+// then answered with the response captured in c; fallback reports whether it responded.
+func newProxy(u *url.URL, c *Cassette, fallback func(w http.ResponseWriter, r *http.Request) bool) http.Handler {
+	p := httputil.NewSingleHostReverseProxy(u)
+	d := p.Director
+	p.Director = func(r *http.Request) {
+		d(r)
+		r.Host = u.Host
+		// let transport negotiate compression, so captured body is stored decoded
+		r.Header.Del("Accept-Encoding")
+	}
+
+	p.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		uri, _ := r.Context().Value(requestURIKey{}).(string)
+
+		if fallback != nil {
+			if x, ok := r.Context().Value(requestKey{}).(*http.Request); ok {
+				if fallback(w, x) {
+					log.Printf("%s\t%s (fallback to blueprint: %s)\n", r.Method, uri, err)
+					return
+				}
+			}
+		}
+
+		if fallback != nil && c != nil {
+			if e := c.find(r.Method, uri); e != nil {
+				log.Printf("%s\t%d\t%s (fallback: %s)\n", e.Method, e.StatusCode, e.URL, err)
+				e.serve(w)
+				return
+			}
+		}
+
+		code := http.StatusBadGateway
+		if r.Context().Err() == context.DeadlineExceeded {
+			code = http.StatusGatewayTimeout
+		}
+
+		log.Printf("%s\t%d\t%s (proxy: %s)\n", r.Method, code, uri, err)
+		w.WriteHeader(code)
+	}
+
+	if c == nil {
+		return p
+	}
+
+	p.ModifyResponse = func(res *http.Response) error {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+
+		res.Body.Close()
+		res.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+		h := http.Header{}
+		for k, vs := range res.Header {
+			if k != "Content-Length" {
+				h[k] = append([]string(nil), vs...)
+			}
+		}
+
+		uri, _ := res.Request.Context().Value(requestURIKey{}).(string)
+
+		e := &CassetteEntry{
+			Method:     res.Request.Method,
+			URL:        uri,
+			StatusCode: res.StatusCode,
+			Header:     h,
+			Body:       string(b),
+		}
+
+		if err := c.Record(e); err != nil {
+			log.Printf("cassette: %s\n", err)
+		}
+
+		return nil
+	}
+
+	return p
+}
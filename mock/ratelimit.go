@@ -0,0 +1,122 @@
+package mock
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit allows each client, identified by IP address, Limit requests per Window. Further
+// requests are responded 429 until the window of the client ends. It is shared across reloads
+// of the blueprint.
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimit creates RateLimit allowing limit requests per window
+func NewRateLimit(limit int, window time.Duration) *RateLimit {
+	return &RateLimit{
+		Limit:   limit,
+		Window:  window,
+		clients: map[string]*rateWindow{},
+	}
+}
+
+// ParseRateLimit parses `<n>/<duration>`, e.g. `10/1m` or `5/s`, the duration defaults to one
+// unit when its number is omitted. Empty string disables rate limiting and returns nil.
+func ParseRateLimit(s string) (*RateLimit, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	z := strings.SplitN(s, "/", 2)
+	if len(z) != 2 {
+		return nil, fmt.Errorf("invalid rate limit: %s, expected <n>/<duration>", s)
+	}
+
+	n, err := strconv.Atoi(z[0])
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid rate limit: %s, expected positive number of requests", s)
+	}
+
+	d := z[1]
+	if d != "" && (d[0] < '0' || d[0] > '9') {
+		d = "1" + d
+	}
+
+	w, err := time.ParseDuration(d)
+	if err != nil || w <= 0 {
+		return nil, fmt.Errorf("invalid rate limit: %s, expected positive duration", s)
+	}
+
+	return NewRateLimit(n, w), nil
+}
+
+// allow counts r against the window of its client, returning the time left in the window when
+// the limit is exceeded
+func (l *RateLimit) allow(r *http.Request) (bool, time.Duration) {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	x, ok := l.clients[ip]
+	if !ok || now.Sub(x.start) >= l.Window {
+		l.prune(now)
+
+		x = &rateWindow{start: now}
+		l.clients[ip] = x
+	}
+
+	if x.count >= l.Limit {
+		return false, x.start.Add(l.Window).Sub(now)
+	}
+
+	x.count++
+	return true, 0
+}
+
+// prune forgets clients whose window ended, keeping memory bounded on long running servers
+func (l *RateLimit) prune(now time.Time) {
+	for k, x := range l.clients {
+		if now.Sub(x.start) >= l.Window {
+			delete(l.clients, k)
+		}
+	}
+}
+
+// limit responds 429 with Retry-After header in seconds when r exceeds the rate limit
+func (l *RateLimit) limit(w http.ResponseWriter, r *http.Request) bool {
+	ok, wait := l.allow(r)
+	if ok {
+		return false
+	}
+
+	secs := int((wait + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(secs))
+	writeMessage(w, http.StatusTooManyRequests)
+
+	return true
+}
@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+type handlerBox struct {
+	http.Handler
+}
+
+// Reloader serves requests using a handler that can be swapped while serving.
+// In-flight requests finish against the handler they started with.
+type Reloader struct {
+	v atomic.Value
+}
+
+// NewReloader returns Reloader serving h
+func NewReloader(h http.Handler) *Reloader {
+	x := &Reloader{}
+	x.Swap(h)
+
+	return x
+}
+
+// Swap replaces the handler used by subsequent requests
+func (x *Reloader) Swap(h http.Handler) {
+	x.v.Store(handlerBox{h})
+}
+
+func (x *Reloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	x.v.Load().(handlerBox).ServeHTTP(w, r)
+}
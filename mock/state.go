@@ -0,0 +1,133 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const stateNotFoundBody = `{"message":"Not Found"}`
+
+// State stores resources created through the mock server, keyed by collection path and id.
+// It is shared across reloads of the blueprint.
+type State struct {
+	mu    sync.Mutex
+	items map[string]map[string][]byte
+	seq   map[string]int
+}
+
+// NewState creates an empty State
+func NewState() *State {
+	return &State{
+		items: map[string]map[string][]byte{},
+		seq:   map[string]int{},
+	}
+}
+
+// serve answers r from stored resources. POST on a collection stores the JSON object of the
+// request, GET on `<collection>/<id>` returns it and DELETE removes it. Other requests, and
+// those the store knows nothing about, return false to be served from blueprint examples.
+func (s *State) serve(r *http.Request, n *MockTransaction) (int, string, bool) {
+	if n.StatusCode < 200 || n.StatusCode >= 300 {
+		return 0, "", false
+	}
+
+	p := path.Clean(r.URL.Path)
+
+	switch r.Method {
+	case http.MethodPost:
+		return s.create(r, p, n.StatusCode)
+	case http.MethodGet:
+		return s.read(p, n.StatusCode)
+	case http.MethodDelete:
+		s.remove(p)
+	}
+
+	return 0, "", false
+}
+
+func (s *State) create(r *http.Request, col string, code int) (int, string, bool) {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, "", false
+	}
+
+	var v map[string]interface{}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	if err = d.Decode(&v); err != nil || v == nil {
+		return 0, "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.items[col] == nil {
+		s.items[col] = map[string][]byte{}
+	}
+
+	if _, ok := v["id"]; !ok {
+		for {
+			s.seq[col]++
+
+			if _, used := s.items[col][strconv.Itoa(s.seq[col])]; !used {
+				v["id"] = s.seq[col]
+				break
+			}
+		}
+	}
+
+	z, err := json.Marshal(v)
+	if err != nil {
+		return 0, "", false
+	}
+
+	s.items[col][fmt.Sprint(v["id"])] = z
+	return code, string(z), true
+}
+
+// read returns a stored resource, or 404 when it was deleted
+func (s *State) read(p string, code int) (int, string, bool) {
+	col, id := splitResource(p)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, ok := s.items[col][id]
+	if !ok {
+		return 0, "", false
+	}
+
+	if z == nil {
+		return http.StatusNotFound, stateNotFoundBody, true
+	}
+
+	return code, string(z), true
+}
+
+// remove marks a resource as deleted, including those only existing in blueprint examples
+func (s *State) remove(p string) {
+	col, id := splitResource(p)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.items[col] == nil {
+		s.items[col] = map[string][]byte{}
+	}
+
+	s.items[col][id] = nil
+}
+
+func splitResource(p string) (string, string) {
+	col, id := path.Split(p)
+	return strings.TrimSuffix(col, "/"), id
+}
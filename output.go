@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"net/url"
+	"os"
+
+	snowboard "github.com/bukalapak/snowboard/parser"
+	xerrors "github.com/pkg/errors"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// ANSI color codes of terminal messages
+const (
+	colorRed    = "31"
+	colorGreen  = "32"
+	colorYellow = "33"
+	colorCyan   = "36"
+	colorDim    = "2"
+)
+
+// noColor disables colors regardless of the writer, set by --no-color flag
+var noColor bool
+
+// paint wraps s in color when w is a terminal, colors are disabled by --no-color or NO_COLOR
+func paint(w io.Writer, color, s string) string {
+	if s == "" || noColor || os.Getenv("NO_COLOR") != "" || !isTerminal(w) {
+		return s
+	}
+
+	return "\x1b[" + color + "m" + s + "\x1b[0m"
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Exit codes of commands, CI scripts rely on them to tell an invalid blueprint from a missing file
+const (
+	exitCodeError   = 1 // usage or runtime error
+	exitCodeInvalid = 2 // blueprint failed validation
+	exitCodeIO      = 3 // reading input or writing output failed
+)
+
+// invalidError marks failures caused by content of the blueprint, exiting with exitCodeInvalid
+type invalidError struct {
+	error
+}
+
+// exitError is cli.NewExitError with message painted red, exiting with exitCodeError
+func exitError(msg string) *cli.ExitError {
+	return exitErrorCode(msg, exitCodeError)
+}
+
+// exitErrorCode is exitError with explicit exit code
+func exitErrorCode(msg string, code int) *cli.ExitError {
+	return cli.NewExitError(paint(cli.ErrWriter, colorRed, msg), code)
+}
+
+// commandError is exitError with exit code chosen by the cause of err, exit errors are kept
+func commandError(err error) *cli.ExitError {
+	if x, ok := err.(*cli.ExitError); ok {
+		return x
+	}
+
+	return exitErrorCode(err.Error(), exitCode(err))
+}
+
+// exitCode returns exitCodeIO for file system and network failures, exitCodeInvalid for
+// validation and parse failures, exitCodeError otherwise
+func exitCode(err error) int {
+	switch xerrors.Cause(err).(type) {
+	case invalidError, *snowboard.ParseError:
+		return exitCodeInvalid
+	case *snowboard.ReadError, *os.PathError, *os.LinkError, *os.SyscallError, *url.Error:
+		return exitCodeIO
+	}
+
+	return exitCodeError
+}
@@ -0,0 +1,39 @@
+package parser_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type warningEngine struct{ fakeEngine }
+
+func (warningEngine) Parse(r io.Reader) ([]byte, error) {
+	return []byte(`{"element": "parseResult", "content": [
+		{"element": "category", "meta": {"classes": ["api"], "title": "API"}, "content": []},
+		{"element": "annotation", "meta": {"classes": ["warning"]}, "attributes": {"code": 6}, "content": "unexpected header"}
+	]}`), nil
+}
+
+func TestLoadWithAnnotations(t *testing.T) {
+	f, err := ioutil.TempFile("", "snowboard")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString("# API\n")
+	f.Close()
+
+	b, ns, err := snowboard.LoadWithAnnotations(f.Name(), warningEngine{})
+	assert.Nil(t, err)
+	assert.Equal(t, "API", b.Title)
+	assert.Len(t, ns, 1)
+	assert.Equal(t, "unexpected header", ns[0].Description)
+	assert.Equal(t, "warning", ns[0].Severity())
+
+	_, _, err = snowboard.LoadWithAnnotations("missing.apib", warningEngine{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+// dataStructureUsage is an attributes section or named data structure referencing other data structures
+type dataStructureUsage struct {
+	d          api.DataStructure
+	where      string
+	sourceMaps []api.SourceMap
+}
+
+// CheckDataStructures warns about named data structures which are not referenced by attributes of any
+// resource, action, request or response, either directly or through other data structures. It also
+// warns about references to data structures which are not defined.
+func CheckDataStructures(b *api.API) []api.Annotation {
+	var ns []api.Annotation
+
+	ds := map[string]api.DataStructure{}
+
+	for _, d := range b.DataStructures {
+		ds[d.Name] = d
+	}
+
+	roots := attributeUsages(b)
+	var us []dataStructureUsage
+
+	// named attributes are data structures on their own, checked below
+	for _, u := range roots {
+		if u.d.Name == "" {
+			us = append(us, u)
+		}
+	}
+
+	for _, d := range b.DataStructures {
+		us = append(us, dataStructureUsage{d: d, where: fmt.Sprintf("data structure '%s'", d.Name), sourceMaps: d.SourceMaps})
+	}
+
+	for _, u := range us {
+		seen := map[string]bool{}
+
+		for _, k := range dataStructureRefs(u.d) {
+			if _, ok := ds[k]; ok || seen[k] {
+				continue
+			}
+
+			seen[k] = true
+
+			ns = append(ns, api.Annotation{
+				Description: fmt.Sprintf("data structure '%s' referenced by %s is not defined", k, u.where),
+				Classes:     []string{"warning"},
+				SourceMaps:  u.sourceMaps,
+			})
+		}
+	}
+
+	used := map[string]bool{}
+
+	var visit func(d api.DataStructure)
+	visit = func(d api.DataStructure) {
+		for _, k := range dataStructureRefs(d) {
+			z, ok := ds[k]
+			if !ok || used[k] {
+				continue
+			}
+
+			used[k] = true
+			visit(z)
+		}
+	}
+
+	for _, u := range roots {
+		d := u.d
+
+		if d.Name != "" {
+			used[d.Name] = true
+
+			if z, ok := ds[d.Name]; ok {
+				d = z
+			}
+		}
+
+		visit(d)
+	}
+
+	for _, d := range b.DataStructures {
+		if used[d.Name] {
+			continue
+		}
+
+		ns = append(ns, api.Annotation{
+			Description: fmt.Sprintf("data structure '%s' is not referenced by any attributes", d.Name),
+			Classes:     []string{"warning"},
+			SourceMaps:  d.SourceMaps,
+		})
+	}
+
+	return ns
+}
+
+func attributeUsages(b *api.API) []dataStructureUsage {
+	var us []dataStructureUsage
+
+	add := func(d api.DataStructure, where string, sm []api.SourceMap) {
+		if d.Kind == "" && d.Name == "" {
+			return
+		}
+
+		us = append(us, dataStructureUsage{d: d, where: where, sourceMaps: sm})
+	}
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			add(r.Attributes, fmt.Sprintf("resource %s", r.Href.Path), r.Href.SourceMaps)
+
+			for _, t := range r.Transitions {
+				add(t.Attributes, fmt.Sprintf("%s %s", t.Method, t.URL), bodySourceMaps(r, t, api.Asset{}))
+
+				for _, x := range t.Transactions {
+					add(x.Request.Attributes, fmt.Sprintf("request of %s %s", t.Method, t.URL), bodySourceMaps(r, t, x.Request.Body))
+					add(x.Response.Attributes, fmt.Sprintf("response %d of %s %s", x.Response.StatusCode, t.Method, t.URL), bodySourceMaps(r, t, x.Response.Body))
+				}
+			}
+		}
+	}
+
+	return us
+}
+
+// dataStructureRefs lists names of data structures d inherits from or contains
+func dataStructureRefs(d api.DataStructure) []string {
+	var xs []string
+
+	if !isBaseType(d.Kind) {
+		xs = append(xs, d.Kind)
+	}
+
+	return append(xs, references(d.Members)...)
+}
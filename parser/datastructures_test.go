@@ -0,0 +1,72 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDataStructures(t *testing.T) {
+	bs := []api.SourceMap{{Row: 14, Col: 9}}
+	ds := []api.SourceMap{{Row: 40, Col: 1}}
+
+	b := &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Href:       api.Href{Path: "/users/{id}"},
+						Attributes: api.DataStructure{Name: "User", Kind: "object"},
+						Transitions: []*api.Transition{
+							{
+								Method:     "POST",
+								URL:        "/users",
+								Attributes: api.DataStructure{Kind: "UserInput"},
+								Transactions: []api.Transaction{
+									{
+										Response: api.Response{
+											StatusCode: 201,
+											Body:       api.Asset{SourceMaps: bs},
+											Attributes: api.DataStructure{Kind: "object", Members: []api.Member{
+												{Key: "user", Kind: "User"},
+												{Key: "links", Kind: "array", Members: []api.Member{{Kind: "Link"}}},
+												{Key: "meta", Kind: "Meta"},
+												{Key: "via", Kind: "select", Members: []api.Member{{Kind: "option"}}},
+											}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		DataStructures: []api.DataStructure{
+			{Name: "User", Kind: "object", Members: []api.Member{{Key: "address", Kind: "Address"}}},
+			{Name: "Address", Kind: "object"},
+			{Name: "UserInput", Kind: "Base"},
+			{Name: "Base", Kind: "object"},
+			{Name: "Meta", Kind: "object"},
+			{Name: "Orphan", Kind: "object", Members: []api.Member{{Key: "child", Kind: "OrphanChild"}, {Key: "x", Kind: "Missing"}}, SourceMaps: ds},
+			{Name: "OrphanChild", Kind: "object"},
+		},
+	}
+
+	ns := snowboard.CheckDataStructures(b)
+
+	if assert.Len(t, ns, 4) {
+		assert.Equal(t, "data structure 'Link' referenced by response 201 of POST /users is not defined", ns[0].Description)
+		assert.Equal(t, bs, ns[0].SourceMaps)
+		assert.Equal(t, "warning", ns[0].Severity())
+		assert.Equal(t, "data structure 'Missing' referenced by data structure 'Orphan' is not defined", ns[1].Description)
+		assert.Equal(t, ds, ns[1].SourceMaps)
+		assert.Equal(t, "data structure 'Orphan' is not referenced by any attributes", ns[2].Description)
+		assert.Equal(t, ds, ns[2].SourceMaps)
+		assert.Equal(t, "data structure 'OrphanChild' is not referenced by any attributes", ns[3].Description)
+	}
+
+	assert.Empty(t, snowboard.CheckDataStructures(&api.API{}))
+}
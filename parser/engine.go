@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/bukalapak/snowboard/adapter/drafter"
+)
+
+// Parser converts API blueprint into API Element JSON
+type Parser interface {
+	Parse(r io.Reader) ([]byte, error)
+	Validate(r io.Reader) ([]byte, error)
+}
+
+// SourceMapParser is implemented by parsers able to include source maps in API Element JSON
+type SourceMapParser interface {
+	ParseWithSourceMap(r io.Reader) ([]byte, error)
+}
+
+// ContextParser is implemented by parsers able to stop working when ctx is done, e.g. by running
+// in a separate process. Drafter can not be interrupted, with it cancellation only returns early.
+type ContextParser interface {
+	ParseContext(ctx context.Context, r io.Reader) ([]byte, error)
+}
+
+// DefaultEngine is the name of parser used unless changed by Use
+const DefaultEngine = "drafter"
+
+var (
+	enginesMu sync.RWMutex
+	engines   = map[string]Parser{}
+	engine    Parser
+)
+
+func init() {
+	Register(DefaultEngine, drafter.Engine{})
+	Use(DefaultEngine)
+}
+
+// Register makes parser available by name, it panics if name is already registered or p is nil
+func Register(name string, p Parser) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+
+	if p == nil {
+		panic("parser: Register parser is nil")
+	}
+
+	if _, ok := engines[name]; ok {
+		panic("parser: Register called twice for " + name)
+	}
+
+	engines[name] = p
+}
+
+// Get returns registered parser by name
+func Get(name string) (Parser, error) {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	p, ok := engines[name]
+	if !ok {
+		return nil, fmt.Errorf("parser: unknown engine %q", name)
+	}
+
+	return p, nil
+}
+
+// Engines returns sorted names of registered parsers
+func Engines() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	xs := make([]string, 0, len(engines))
+	for k := range engines {
+		xs = append(xs, k)
+	}
+
+	sort.Strings(xs)
+	return xs
+}
+
+// Use selects registered parser used by package functions
+func Use(name string) error {
+	p, err := Get(name)
+	if err != nil {
+		return err
+	}
+
+	enginesMu.Lock()
+	engine = p
+	enginesMu.Unlock()
+
+	return nil
+}
+
+func currentEngine() Parser {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	return engine
+}
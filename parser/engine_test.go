@@ -0,0 +1,53 @@
+package parser_test
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEngine struct{}
+
+func (fakeEngine) Parse(r io.Reader) ([]byte, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(`{"element": "parseResult", "content": [{"element": "category", "meta": {"classes": ["api"], "title": "` + strings.TrimPrefix(string(b), "# ") + `"}, "content": []}]}`), nil
+}
+
+func (fakeEngine) Validate(r io.Reader) ([]byte, error) {
+	return nil, nil
+}
+
+func TestRegister(t *testing.T) {
+	snowboard.Register("fake", fakeEngine{})
+
+	p, err := snowboard.Get("fake")
+	assert.Nil(t, err)
+	assert.Equal(t, fakeEngine{}, p)
+	assert.Equal(t, []string{"drafter", "fake"}, snowboard.Engines())
+
+	assert.Panics(t, func() { snowboard.Register("fake", fakeEngine{}) })
+	assert.Panics(t, func() { snowboard.Register("nil", nil) })
+
+	_, err = snowboard.Get("unknown")
+	assert.EqualError(t, err, `parser: unknown engine "unknown"`)
+	assert.NotNil(t, snowboard.Use("unknown"))
+
+	assert.Nil(t, snowboard.Use("fake"))
+	defer snowboard.Use(snowboard.DefaultEngine)
+
+	b, err := snowboard.Parse(strings.NewReader("# API"))
+	assert.Nil(t, err)
+	assert.Equal(t, "API", b.Title)
+
+	b, err = snowboard.Validate(strings.NewReader("# API"))
+	assert.Nil(t, err)
+	assert.Nil(t, b)
+}
@@ -0,0 +1,44 @@
+package parser
+
+import "io"
+
+// ReadError reports failure reading API blueprint, its includes or seeds, e.g. missing file or
+// unreachable URL. Its message is the one of Err.
+type ReadError struct {
+	Name string
+	Err  error
+}
+
+func (e *ReadError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying read failure
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError reports failure of the parser engine, e.g. drafter returning non-zero code. Its
+// message is the one of Err.
+type ParseError struct {
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying engine failure
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// runEngine calls engine fn, its failure is returned as ParseError
+func runEngine(fn func(io.Reader) ([]byte, error), r io.Reader) ([]byte, error) {
+	b, err := fn(r)
+	if err != nil {
+		return nil, &ParseError{Err: err}
+	}
+
+	return b, nil
+}
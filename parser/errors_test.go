@@ -0,0 +1,43 @@
+package parser_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingEngine struct{ fakeEngine }
+
+func (failingEngine) Parse(r io.Reader) ([]byte, error) {
+	return nil, errors.New("Parse failed with code: 4")
+}
+
+func TestLoad_errors(t *testing.T) {
+	_, _, err := snowboard.LoadWithAnnotations("missing.apib", fakeEngine{})
+	assert.NotNil(t, err)
+
+	x, ok := err.(*snowboard.ReadError)
+	assert.True(t, ok)
+	assert.Equal(t, "missing.apib", x.Name)
+	assert.Equal(t, x.Err, x.Unwrap())
+	assert.Equal(t, x.Err.Error(), err.Error())
+
+	f, err := ioutil.TempFile("", "snowboard")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString("# API\n")
+	f.Close()
+
+	_, _, err = snowboard.LoadWithAnnotations(f.Name(), failingEngine{})
+	assert.EqualError(t, err, "Parse failed with code: 4")
+
+	z, ok := err.(*snowboard.ParseError)
+	assert.True(t, ok)
+	assert.EqualError(t, z.Unwrap(), "Parse failed with code: 4")
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/schema"
+)
+
+// CheckExamples validates JSON response examples against their schema. Mismatches are
+// reported as warning annotations pointing at the example body.
+func CheckExamples(b *api.API) []api.Annotation {
+	var ns []api.Annotation
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				for _, x := range t.Transactions {
+					ns = append(ns, checkExample(t, x.Response)...)
+				}
+			}
+		}
+	}
+
+	return ns
+}
+
+func checkExample(t *api.Transition, res api.Response) []api.Annotation {
+	if res.Schema.Body == "" || !strings.Contains(res.Body.ContentType, "json") || strings.TrimSpace(res.Body.Body) == "" {
+		return nil
+	}
+
+	var ms []string
+
+	errs, err := schema.Validate([]byte(res.Schema.Body), []byte(res.Body.Body))
+	if err != nil {
+		ms = append(ms, err.Error())
+	}
+
+	for _, e := range errs {
+		ms = append(ms, e.Error())
+	}
+
+	var ns []api.Annotation
+
+	for _, m := range ms {
+		ns = append(ns, api.Annotation{
+			Description: fmt.Sprintf("example of response %d of %s %s does not match schema: %s", res.StatusCode, t.Method, t.URL, m),
+			Classes:     []string{"warning"},
+			SourceMaps:  res.Body.SourceMaps,
+		})
+	}
+
+	return ns
+}
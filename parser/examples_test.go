@@ -0,0 +1,46 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckExamples(t *testing.T) {
+	sm := []api.SourceMap{{Row: 120, Col: 18}}
+	sc := api.Asset{ContentType: "application/schema+json", Body: `{"type": "object", "required": ["id"], "properties": {"id": {"type": "number"}}}`}
+
+	b := &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Transitions: []*api.Transition{
+							{
+								Method: "GET",
+								URL:    "/messages/1",
+								Transactions: []api.Transaction{
+									{Response: api.Response{StatusCode: 200, Schema: sc, Body: api.Asset{ContentType: "application/json", Body: `{"id": 1}`}}},
+									{Response: api.Response{StatusCode: 201, Schema: sc, Body: api.Asset{ContentType: "application/json", Body: `{"id": "1"}`, SourceMaps: sm}}},
+									{Response: api.Response{StatusCode: 202, Schema: sc, Body: api.Asset{ContentType: "text/plain", Body: `hello`}}},
+									{Response: api.Response{StatusCode: 204, Schema: sc}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ns := snowboard.CheckExamples(b)
+	assert.Equal(t, []api.Annotation{
+		{
+			Description: "example of response 201 of GET /messages/1 does not match schema: id: must be of type number",
+			Classes:     []string{"warning"},
+			SourceMaps:  sm,
+		},
+	}, ns)
+}
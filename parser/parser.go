@@ -3,16 +3,48 @@ package parser
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"io/ioutil"
 
-	"github.com/bukalapak/snowboard/adapter/drafter"
 	"github.com/bukalapak/snowboard/api"
 	"github.com/bukalapak/snowboard/loader"
 )
 
 // Parse formats API blueprint as blueprint.API struct
 func Parse(r io.Reader) (*api.API, error) {
-	el, err := parseElement(r)
+	return ParseContext(context.Background(), r, nil)
+}
+
+// ParseContext formats API blueprint as blueprint.API struct using engine p, or the one selected
+// by Use when p is nil. It returns early when ctx is done, see ContextParser.
+func ParseContext(ctx context.Context, r io.Reader, p Parser) (*api.API, error) {
+	el, err := parseElementContext(ctx, r, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.NewAPI(el)
+}
+
+// ParseWithSourceMaps formats API blueprint as blueprint.API struct, assets keep their source maps
+func ParseWithSourceMaps(r io.Reader) (*api.API, error) {
+	p := currentEngine()
+
+	var b []byte
+	var err error
+
+	if x, ok := p.(SourceMapParser); ok {
+		b, err = withProgress(r, x.ParseWithSourceMap)
+	} else {
+		b, err = withProgress(r, p.Parse)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	el, err := api.ParseJSON(bytes.NewReader(b))
 	if err != nil {
 		return nil, err
 	}
@@ -22,7 +54,7 @@ func Parse(r io.Reader) (*api.API, error) {
 
 // ParseAsJSON parse API blueprint as API Element JSON
 func ParseAsJSON(r io.Reader) ([]byte, error) {
-	return drafter.Parse(r)
+	return withProgress(r, currentEngine().Parse)
 }
 
 // Validate validates API blueprint
@@ -39,19 +71,66 @@ func Validate(r io.Reader) (*api.API, error) {
 	return api.NewAPI(el)
 }
 
-// Load reads API blueprint from file as blueprint.API struct
+// Read reads API blueprint from file with its includes and seeds expanded, see loader.Load
+func Read(name string) ([]byte, error) {
+	return ReadContext(context.Background(), name)
+}
+
+// ReadContext is Read returning early when ctx is done, cancelling requests of URL input and remote
+// includes. Read failures are returned as ReadError, cancellation as ctx.Err().
+func ReadContext(ctx context.Context, name string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b, err := loader.LoadContext(ctx, name)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, &ReadError{Name: name, Err: err}
+	}
+
+	return b, nil
+}
+
+// Load reads API blueprint from file as blueprint.API struct. Read failures are returned as
+// ReadError, parser engine failures as ParseError.
 func Load(name string) (*api.API, error) {
-	b, err := loader.Load(name)
+	return LoadContext(context.Background(), name, nil)
+}
+
+// LoadContext reads API blueprint from file as blueprint.API struct using engine p, or the one
+// selected by Use when p is nil. It returns early when ctx is done, see ContextParser.
+func LoadContext(ctx context.Context, name string, p Parser) (*api.API, error) {
+	bp, _, err := loadContext(ctx, name, p)
+	return bp, err
+}
+
+// LoadWithAnnotations reads API blueprint from file using engine p, or the one selected by Use when p is nil.
+// Annotations, such as warnings of successful parse, are returned along the blueprint.
+func LoadWithAnnotations(name string, p Parser) (*api.API, []api.Annotation, error) {
+	return loadContext(context.Background(), name, p)
+}
+
+func loadContext(ctx context.Context, name string, p Parser) (*api.API, []api.Annotation, error) {
+	b, err := ReadContext(ctx, name)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return Parse(bytes.NewReader(b))
+	bp, err := ParseContext(ctx, bytes.NewReader(b), p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bp, bp.Annotations, nil
 }
 
 // LoadAsJSON reads API blueprint from file as API Element JSON
 func LoadAsJSON(name string) ([]byte, error) {
-	b, err := loader.Load(name)
+	b, err := Read(name)
 	if err != nil {
 		return nil, err
 	}
@@ -59,17 +138,66 @@ func LoadAsJSON(name string) ([]byte, error) {
 	return ParseAsJSON(bytes.NewReader(b))
 }
 
-func parseElement(r io.Reader) (*api.Element, error) {
-	b, err := ParseAsJSON(r)
+// parseElementContext parses r using engine p, or the selected one when p is nil. Engines
+// implementing ContextParser are cancelled with ctx, others keep working in background until they
+// finish, their result is discarded.
+func parseElementContext(ctx context.Context, r io.Reader, p Parser) (*api.Element, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		p = currentEngine()
+	}
+
+	b, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
-	return api.ParseJSON(bytes.NewReader(b))
+	if x, ok := p.(ContextParser); ok {
+		z, err := withProgress(bytes.NewReader(b), func(r io.Reader) ([]byte, error) {
+			return x.ParseContext(ctx, r)
+		})
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return api.ParseJSON(bytes.NewReader(z))
+	}
+
+	type result struct {
+		b   []byte
+		err error
+	}
+
+	// buffered, so the goroutine exits once the engine returns even when nobody receives
+	c := make(chan result, 1)
+
+	go func() {
+		z, err := withProgress(bytes.NewReader(b), p.Parse)
+		c <- result{z, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case x := <-c:
+		if x.err != nil {
+			return nil, x.err
+		}
+
+		return api.ParseJSON(bytes.NewReader(x.b))
+	}
 }
 
 func validateElement(r io.Reader) (*api.Element, error) {
-	b, err := drafter.Validate(r)
+	b, err := withProgress(r, currentEngine().Validate)
 	if err != nil {
 		return nil, err
 	}
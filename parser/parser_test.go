@@ -1,8 +1,13 @@
 package parser_test
 
 import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	snowboard "github.com/bukalapak/snowboard/parser"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +21,15 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, "API", api.Title)
 }
 
+func TestParseContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	api, err := snowboard.ParseContext(ctx, strings.NewReader("# API"), nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, api)
+}
+
 func TestParseAsJSON(t *testing.T) {
 	s := strings.NewReader("# API")
 
@@ -52,6 +66,70 @@ func TestLoad(t *testing.T) {
 	assert.Equal(t, []string{"foo", "bar", "baz"}, api.ResourceGroups[0].Resources[0].Transitions[0].Href.Parameters[0].Members)
 }
 
+func TestLoadContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	api, err := snowboard.LoadContext(ctx, "../fixtures/partials/API.apib", nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, api)
+
+	api, err = snowboard.LoadContext(context.Background(), "../fixtures/partials/API.apib", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "API", api.Title)
+}
+
+type blockingEngine struct {
+	fakeEngine
+	stopped chan error
+}
+
+func (e blockingEngine) ParseContext(ctx context.Context, r io.Reader) ([]byte, error) {
+	<-ctx.Done()
+	e.stopped <- ctx.Err()
+
+	return nil, ctx.Err()
+}
+
+func TestLoadContext_engine(t *testing.T) {
+	f, err := ioutil.TempFile("", "snowboard")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString("# API")
+	f.Close()
+
+	api, err := snowboard.LoadContext(context.Background(), f.Name(), fakeEngine{})
+	assert.Nil(t, err)
+	assert.Equal(t, "API", api.Title)
+
+	e := blockingEngine{stopped: make(chan error, 1)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	api, err = snowboard.LoadContext(ctx, f.Name(), e)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Nil(t, api)
+	assert.Equal(t, context.DeadlineExceeded, <-e.stopped)
+}
+
+func TestReadContext(t *testing.T) {
+	b, err := snowboard.Read("../fixtures/partials/API.apib")
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "# Group Users")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = snowboard.ReadContext(ctx, "../fixtures/partials/API.apib")
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = snowboard.ReadContext(context.Background(), "missing.apib")
+	_, ok := err.(*snowboard.ReadError)
+	assert.True(t, ok)
+}
+
 func TestLoad_partials(t *testing.T) {
 	api, err := snowboard.Load("../fixtures/partials/API.apib")
 	assert.Nil(t, err)
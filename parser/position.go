@@ -0,0 +1,28 @@
+package parser
+
+import "unicode/utf8"
+
+// Position converts byte offset within src into 1-based line and column.
+// Columns count characters, so multi-byte UTF-8 sequences count as one.
+func Position(src []byte, offset int) (line, col int) {
+	if offset > len(src) {
+		offset = len(src)
+	}
+
+	line, col = 1, 1
+
+	for i := 0; i < offset; {
+		r, n := utf8.DecodeRune(src[i:])
+		i += n
+
+		if r == '\n' {
+			line++
+			col = 1
+			continue
+		}
+
+		col++
+	}
+
+	return line, col
+}
@@ -0,0 +1,33 @@
+package parser_test
+
+import (
+	"testing"
+
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPosition(t *testing.T) {
+	src := []byte("# API\n\n## Café ☕ [/x]\n+ Response 200\n")
+
+	cases := []struct {
+		offset int
+		line   int
+		col    int
+	}{
+		{0, 1, 1},
+		{2, 1, 3},
+		{6, 2, 1},
+		{7, 3, 1},
+		{16, 3, 9},
+		{20, 3, 11},
+		{25, 4, 1},
+		{999, 5, 1},
+	}
+
+	for _, c := range cases {
+		line, col := snowboard.Position(src, c.offset)
+		assert.Equal(t, c.line, line, "line of %d", c.offset)
+		assert.Equal(t, c.col, col, "col of %d", c.offset)
+	}
+}
@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+)
+
+// Progress, when set, is called with done false before the engine starts parsing size bytes
+// of API blueprint and with done true after it finishes. Parsing may run concurrently, e.g.
+// when loading several blueprints, so implementations must be safe for concurrent use.
+var Progress func(size int, done bool)
+
+func withProgress(r io.Reader, fn func(io.Reader) ([]byte, error)) ([]byte, error) {
+	p := Progress
+	if p == nil {
+		return runEngine(fn, r)
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p(len(b), false)
+	defer p(len(b), true)
+
+	return runEngine(fn, bytes.NewReader(b))
+}
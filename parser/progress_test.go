@@ -0,0 +1,29 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	snowboard.Register("progress", fakeEngine{})
+
+	assert.Nil(t, snowboard.Use("progress"))
+	defer snowboard.Use(snowboard.DefaultEngine)
+
+	var calls []bool
+
+	snowboard.Progress = func(size int, done bool) {
+		assert.Equal(t, 5, size)
+		calls = append(calls, done)
+	}
+	defer func() { snowboard.Progress = nil }()
+
+	b, err := snowboard.Parse(strings.NewReader("# API"))
+	assert.Nil(t, err)
+	assert.Equal(t, "API", b.Title)
+	assert.Equal(t, []bool{false, true}, calls)
+}
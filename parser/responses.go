@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+// CheckResponses reports transitions without any response as warning annotations pointing at
+// their URI, or URI of the resource when the transition has none.
+func CheckResponses(b *api.API) []api.Annotation {
+	var ns []api.Annotation
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				if hasResponse(t) {
+					continue
+				}
+
+				sm := t.Href.SourceMaps
+				if len(sm) == 0 {
+					sm = r.Href.SourceMaps
+				}
+
+				ns = append(ns, api.Annotation{
+					Description: fmt.Sprintf("%s %s has no response", t.Method, t.URL),
+					Classes:     []string{"warning"},
+					SourceMaps:  sm,
+				})
+			}
+		}
+	}
+
+	return ns
+}
+
+func hasResponse(t *api.Transition) bool {
+	for _, x := range t.Transactions {
+		if x.Response.StatusCode != 0 || x.Response.Status != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckResponses(t *testing.T) {
+	rs := []api.SourceMap{{Row: 10, Col: 22}}
+	ts := []api.SourceMap{{Row: 80, Col: 30}}
+
+	b := &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Href: api.Href{Path: "/users", SourceMaps: rs},
+						Transitions: []*api.Transition{
+							{
+								Method:       "GET",
+								URL:          "/users",
+								Transactions: []api.Transaction{{Response: api.Response{StatusCode: 200}}},
+							},
+							{
+								Method:       "POST",
+								URL:          "/users",
+								Transactions: []api.Transaction{{Request: api.Request{Method: "POST"}}},
+							},
+							{
+								Method: "DELETE",
+								URL:    "/users/{id}",
+								Href:   api.Href{Path: "/users/{id}", SourceMaps: ts},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ns := snowboard.CheckResponses(b)
+	assert.Equal(t, []api.Annotation{
+		{
+			Description: "POST /users has no response",
+			Classes:     []string{"warning"},
+			SourceMaps:  rs,
+		},
+		{
+			Description: "DELETE /users/{id} has no response",
+			Classes:     []string{"warning"},
+			SourceMaps:  ts,
+		},
+	}, ns)
+}
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"encoding/json"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+const jsonSchemaDraft4 = "http://json-schema.org/draft-04/schema#"
+
+// Schemas returns JSON Schema (draft 4) of every named data structure keyed by its name.
+// Referenced data structures are embedded as definitions and referred by `$ref`, so every
+// schema stands alone. Recursive structures refer to their own definition instead of being
+// expanded.
+func Schemas(bp *api.API) map[string][]byte {
+	ds := map[string]api.DataStructure{}
+
+	for _, d := range bp.DataStructures {
+		ds[d.Name] = d
+	}
+
+	xs := map[string][]byte{}
+
+	for _, d := range bp.DataStructures {
+		s := jsonSchema(api.Member{Kind: d.Kind, Description: d.Description, Members: d.Members})
+		s["$schema"] = jsonSchemaDraft4
+
+		defs := map[string]interface{}{}
+		collectDefinitions(d, ds, defs)
+
+		if len(defs) > 0 {
+			s["definitions"] = defs
+		}
+
+		b, err := json.Marshal(s)
+		if err != nil {
+			continue
+		}
+
+		xs[d.Name] = b
+	}
+
+	return xs
+}
+
+// collectDefinitions adds schemas of data structures reachable from d to defs, each once. d itself
+// is only added when it is reachable, i.e. recursive.
+func collectDefinitions(d api.DataStructure, ds map[string]api.DataStructure, defs map[string]interface{}) {
+	refs := references(d.Members)
+
+	if !isBaseType(d.Kind) {
+		refs = append(refs, d.Kind)
+	}
+
+	for _, k := range refs {
+		if _, ok := defs[k]; ok {
+			continue
+		}
+
+		z, ok := ds[k]
+		if !ok {
+			continue
+		}
+
+		defs[k] = jsonSchema(api.Member{Kind: z.Kind, Description: z.Description, Members: z.Members})
+		collectDefinitions(z, ds, defs)
+	}
+}
+
+func references(ms []api.Member) []string {
+	var xs []string
+
+	for _, m := range ms {
+		if !isBaseType(m.Kind) {
+			xs = append(xs, m.Kind)
+		}
+
+		xs = append(xs, references(m.Members)...)
+	}
+
+	return xs
+}
+
+func isBaseType(kind string) bool {
+	switch kind {
+	case "", "string", "number", "boolean", "object", "array", "enum", "select", "option":
+		return true
+	}
+
+	return false
+}
+
+func jsonSchema(m api.Member) map[string]interface{} {
+	s := map[string]interface{}{}
+
+	switch m.Kind {
+	case "string", "number", "boolean":
+		s["type"] = m.Kind
+	case "object":
+		s["type"] = "object"
+
+		props := map[string]interface{}{}
+		required := []string{}
+		mixins := []interface{}{}
+
+		for _, n := range m.Members {
+			if n.Key == "" {
+				mixins = append(mixins, jsonSchema(n))
+				continue
+			}
+
+			props[n.Key] = jsonSchema(n)
+
+			if n.Required {
+				required = append(required, n.Key)
+			}
+		}
+
+		if len(props) > 0 {
+			s["properties"] = props
+		}
+
+		if len(required) > 0 {
+			s["required"] = required
+		}
+
+		if len(mixins) > 0 {
+			s = map[string]interface{}{"allOf": append(mixins, s)}
+		}
+	case "array":
+		s["type"] = "array"
+
+		if len(m.Members) > 0 {
+			s["items"] = jsonSchema(m.Members[0])
+		}
+	case "enum":
+		values := []interface{}{}
+
+		for _, n := range m.Members {
+			values = append(values, n.Value)
+		}
+
+		s["enum"] = values
+	case "":
+	default:
+		s["$ref"] = "#/definitions/" + m.Kind
+	}
+
+	if m.Description != "" && s["$ref"] == nil {
+		s["description"] = m.Description
+	}
+
+	return s
+}
@@ -0,0 +1,138 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemas(t *testing.T) {
+	bp := &api.API{
+		DataStructures: []api.DataStructure{
+			{
+				Name: "User",
+				Kind: "object",
+				Members: []api.Member{
+					{Key: "name", Kind: "string", Required: true},
+					{Key: "address", Kind: "Address"},
+				},
+			},
+			{
+				Name: "Address",
+				Kind: "object",
+				Members: []api.Member{
+					{Key: "city", Kind: "string", Description: "City name"},
+				},
+			},
+			{
+				Name: "Admin",
+				Kind: "User",
+			},
+		},
+	}
+
+	xs := snowboard.Schemas(bp)
+	assert.Len(t, xs, 3)
+
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"address": {"$ref": "#/definitions/Address"}
+		},
+		"required": ["name"],
+		"definitions": {
+			"Address": {
+				"type": "object",
+				"properties": {"city": {"type": "string", "description": "City name"}}
+			}
+		}
+	}`, string(xs["User"]))
+
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {"city": {"type": "string", "description": "City name"}}
+	}`, string(xs["Address"]))
+
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"$ref": "#/definitions/User",
+		"definitions": {
+			"User": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"address": {"$ref": "#/definitions/Address"}
+				},
+				"required": ["name"]
+			},
+			"Address": {
+				"type": "object",
+				"properties": {"city": {"type": "string", "description": "City name"}}
+			}
+		}
+	}`, string(xs["Admin"]))
+}
+
+func TestSchemas_recursive(t *testing.T) {
+	bp := &api.API{
+		DataStructures: []api.DataStructure{
+			{
+				Name: "Category",
+				Kind: "object",
+				Members: []api.Member{
+					{Key: "name", Kind: "string"},
+					{Key: "children", Kind: "array", Members: []api.Member{{Kind: "Category"}}},
+				},
+			},
+			{
+				Name: "Person",
+				Kind: "object",
+				Members: []api.Member{
+					{Key: "company", Kind: "Company"},
+				},
+			},
+			{
+				Name: "Company",
+				Kind: "object",
+				Members: []api.Member{
+					{Key: "owner", Kind: "Person"},
+				},
+			},
+		},
+	}
+
+	xs := snowboard.Schemas(bp)
+
+	category := `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"children": {"type": "array", "items": {"$ref": "#/definitions/Category"}}
+		}
+	}`
+
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"children": {"type": "array", "items": {"$ref": "#/definitions/Category"}}
+		},
+		"definitions": {"Category": `+category+`}
+	}`, string(xs["Category"]))
+
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {"company": {"$ref": "#/definitions/Company"}},
+		"definitions": {
+			"Company": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Person"}}},
+			"Person": {"type": "object", "properties": {"company": {"$ref": "#/definitions/Company"}}}
+		}
+	}`, string(xs["Person"]))
+}
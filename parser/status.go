@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+// CheckStatusCodes reports response status codes which are not a number between 100 and 599
+// as errors, and those without standard meaning as warnings. Codes listed in allowed are
+// intentional custom codes and never reported.
+func CheckStatusCodes(b *api.API, allowed []int) []api.Annotation {
+	var ns []api.Annotation
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				for _, x := range t.Transactions {
+					s := x.Response.Status
+					if s == "" {
+						continue
+					}
+
+					n, err := strconv.Atoi(s)
+
+					switch {
+					case err == nil && containsInt(allowed, n):
+						continue
+					case err != nil || n < 100 || n > 599:
+						ns = append(ns, api.Annotation{
+							Description: fmt.Sprintf("response status code '%s' of %s %s is not a valid HTTP status code", s, t.Method, t.URL),
+							Classes:     []string{"error"},
+							SourceMaps:  bodySourceMaps(r, t, x.Response.Body),
+						})
+					case http.StatusText(n) == "":
+						ns = append(ns, api.Annotation{
+							Description: fmt.Sprintf("response status code %d of %s %s is not a standard HTTP status code", n, t.Method, t.URL),
+							Classes:     []string{"warning"},
+							SourceMaps:  bodySourceMaps(r, t, x.Response.Body),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return ns
+}
+
+// bodySourceMaps points at a message body, falling back to URI of the transition or its resource
+func bodySourceMaps(r *api.Resource, t *api.Transition, a api.Asset) []api.SourceMap {
+	if len(a.SourceMaps) > 0 {
+		return a.SourceMaps
+	}
+
+	if len(t.Href.SourceMaps) > 0 {
+		return t.Href.SourceMaps
+	}
+
+	return r.Href.SourceMaps
+}
+
+func containsInt(xs []int, n int) bool {
+	for _, x := range xs {
+		if x == n {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStatusCodes(t *testing.T) {
+	rs := []api.SourceMap{{Row: 10, Col: 22}}
+	bs := []api.SourceMap{{Row: 14, Col: 9}}
+
+	response := func(s string, code int, sm []api.SourceMap) api.Transaction {
+		return api.Transaction{Response: api.Response{Status: s, StatusCode: code, Body: api.Asset{SourceMaps: sm}}}
+	}
+
+	b := &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Href: api.Href{Path: "/users", SourceMaps: rs},
+						Transitions: []*api.Transition{
+							{
+								Method: "GET",
+								URL:    "/users",
+								Transactions: []api.Transaction{
+									response("200", 200, nil),
+									response("20O", 0, bs),
+									response("999", 999, nil),
+									response("299", 299, nil),
+									response("599", 599, nil),
+									{Request: api.Request{Method: "GET"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ns := snowboard.CheckStatusCodes(b, []int{599})
+	assert.Equal(t, []api.Annotation{
+		{
+			Description: "response status code '20O' of GET /users is not a valid HTTP status code",
+			Classes:     []string{"error"},
+			SourceMaps:  bs,
+		},
+		{
+			Description: "response status code '999' of GET /users is not a valid HTTP status code",
+			Classes:     []string{"error"},
+			SourceMaps:  rs,
+		},
+		{
+			Description: "response status code 299 of GET /users is not a standard HTTP status code",
+			Classes:     []string{"warning"},
+			SourceMaps:  rs,
+		},
+	}, ns)
+
+	assert.Empty(t, snowboard.CheckStatusCodes(b, []int{999, 299, 599})[1:])
+}
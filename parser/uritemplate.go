@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+var templateExpression = regexp.MustCompile(`\{([^}]*)\}`)
+
+// CheckURITemplates cross-checks URI template variables against declared parameters. Undeclared
+// variables and unused parameters are reported as warning annotations pointing at the URI.
+func CheckURITemplates(b *api.API) []api.Annotation {
+	var ns []api.Annotation
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			if r.Href.Path != "" {
+				// Resource parameters may be used by URI templates of its transitions only
+				vs := templateVariables(r.Href.Path)
+				for _, t := range r.Transitions {
+					vs = append(vs, templateVariables(t.Href.Path)...)
+				}
+
+				ns = append(ns, unusedParameters(vs, r.Href.Parameters, r.Href.Path, r.Href.SourceMaps)...)
+			}
+
+			for _, t := range r.Transitions {
+				h := t.Href
+				if h.Path == "" {
+					h.Path = r.Href.Path
+					h.SourceMaps = r.Href.SourceMaps
+				}
+
+				if len(h.SourceMaps) == 0 {
+					h.SourceMaps = r.Href.SourceMaps
+				}
+
+				ps := append(append([]api.Parameter{}, t.Href.Parameters...), r.Href.Parameters...)
+				name := fmt.Sprintf("%s %s", t.Method, h.Path)
+
+				for _, v := range templateVariables(h.Path) {
+					if !hasParameter(ps, v) {
+						ns = append(ns, api.Annotation{
+							Description: fmt.Sprintf("URI template variable '%s' of %s is not declared as parameter", v, name),
+							Classes:     []string{"warning"},
+							SourceMaps:  h.SourceMaps,
+						})
+					}
+				}
+
+				if t.Href.Path != "" {
+					ns = append(ns, unusedParameters(templateVariables(h.Path), t.Href.Parameters, name, h.SourceMaps)...)
+				}
+			}
+		}
+	}
+
+	return ns
+}
+
+func unusedParameters(vs []string, ps []api.Parameter, name string, sm []api.SourceMap) []api.Annotation {
+	var ns []api.Annotation
+
+	for _, p := range ps {
+		if !contains(vs, p.Key) {
+			ns = append(ns, api.Annotation{
+				Description: fmt.Sprintf("parameter '%s' is not used in URI template of %s", p.Key, name),
+				Classes:     []string{"warning"},
+				SourceMaps:  sm,
+			})
+		}
+	}
+
+	return ns
+}
+
+// templateVariables lists variable names of RFC 6570 URI template, operators and modifiers are stripped
+func templateVariables(s string) []string {
+	var vs []string
+
+	for _, m := range templateExpression.FindAllStringSubmatch(s, -1) {
+		e := strings.TrimLeft(m[1], "+#./;?&")
+
+		for _, v := range strings.Split(e, ",") {
+			v = strings.TrimSuffix(strings.TrimSpace(v), "*")
+
+			if i := strings.Index(v, ":"); i >= 0 {
+				v = v[:i]
+			}
+
+			if v != "" && !contains(vs, v) {
+				vs = append(vs, v)
+			}
+		}
+	}
+
+	return vs
+}
+
+func hasParameter(ps []api.Parameter, key string) bool {
+	for _, p := range ps {
+		if p.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(xs []string, s string) bool {
+	for _, x := range xs {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
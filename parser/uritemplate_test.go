@@ -0,0 +1,55 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckURITemplates(t *testing.T) {
+	rs := []api.SourceMap{{Row: 10, Col: 22}}
+	ts := []api.SourceMap{{Row: 80, Col: 30}}
+
+	b := &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Href: api.Href{
+							Path:       "/users/{userId}",
+							Parameters: []api.Parameter{{Key: "user_id"}, {Key: "page"}},
+							SourceMaps: rs,
+						},
+						Transitions: []*api.Transition{
+							{Method: "GET"},
+							{
+								Method: "PATCH",
+								Href: api.Href{
+									Path:       "/users/{user_id}{?page,fields*}",
+									Parameters: []api.Parameter{{Key: "fields"}, {Key: "sort"}},
+									SourceMaps: ts,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ns := snowboard.CheckURITemplates(b)
+	assert.Equal(t, []api.Annotation{
+		{
+			Description: "URI template variable 'userId' of GET /users/{userId} is not declared as parameter",
+			Classes:     []string{"warning"},
+			SourceMaps:  rs,
+		},
+		{
+			Description: "parameter 'sort' is not used in URI template of PATCH /users/{user_id}{?page,fields*}",
+			Classes:     []string{"warning"},
+			SourceMaps:  ts,
+		},
+	}, ns)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	snowboard "github.com/bukalapak/snowboard/parser"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// spinnerDelay keeps parsing of small blueprints silent
+const spinnerDelay = 500 * time.Millisecond
+
+// spinner writes a spinner to w while API blueprints are parsed, concurrent parses share it
+type spinner struct {
+	w      io.Writer
+	mu     sync.Mutex
+	active int
+	size   int
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func (s *spinner) progress(size int, done bool) {
+	s.mu.Lock()
+
+	if !done {
+		s.active++
+		s.size += size
+
+		if s.active == 1 {
+			s.stop = make(chan struct{})
+			s.done = make(chan struct{})
+			go s.spin(s.stop, s.done)
+		}
+
+		s.mu.Unlock()
+		return
+	}
+
+	s.active--
+	s.size -= size
+
+	if s.active > 0 {
+		s.mu.Unlock()
+		return
+	}
+
+	stop, d := s.stop, s.done
+	s.mu.Unlock()
+
+	// spin reads size under the lock, wait for it after unlocking
+	close(stop)
+	<-d
+}
+
+func (s *spinner) spin(stop, done chan struct{}) {
+	defer close(done)
+
+	select {
+	case <-stop:
+		return
+	case <-time.After(spinnerDelay):
+	}
+
+	t := time.NewTicker(100 * time.Millisecond)
+	defer t.Stop()
+
+	frames := `|/-\`
+
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		size := s.size
+		s.mu.Unlock()
+
+		fmt.Fprintf(s.w, "\r%c Parsing API blueprint (%d KB)...", frames[i%len(frames)], size/1024)
+
+		select {
+		case <-stop:
+			fmt.Fprint(s.w, "\r\x1b[K")
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// setupProgress shows spinner on stderr while parsing, unless quiet or stderr is not a terminal
+func setupProgress(c *cli.Context) {
+	if c.Bool("q") || !isTerminal(c.App.ErrWriter) {
+		snowboard.Progress = nil
+		return
+	}
+
+	s := &spinner{w: c.App.ErrWriter}
+	snowboard.Progress = s.progress
+}
@@ -0,0 +1,57 @@
+package render
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+var (
+	headingPattern      = regexp.MustCompile(`^(#+)\s`)
+	groupHeadingPattern = regexp.MustCompile(`^(#+)\s*Group\s+(.+?)[\s#]*$`)
+)
+
+// APIBOptions configures API blueprint output
+type APIBOptions struct {
+	// Only keeps resource groups of given titles, see api.MatchGroup
+	Only []string
+	// Exclude drops resource groups of given titles
+	Exclude []string
+}
+
+// APIB renders API blueprint, e.g. of loader.Load, keeping resource groups selected by opt
+func APIB(w io.Writer, b []byte, opt APIBOptions) error {
+	_, err := w.Write(filterAPIB(b, opt.Only, opt.Exclude))
+	return err
+}
+
+// filterAPIB drops sections of unmatched resource groups from API blueprint, a section
+// ends at the next heading of the same or higher level, e.g. `# Data Structures`.
+func filterAPIB(b []byte, only, exclude []string) []byte {
+	if len(only) == 0 && len(exclude) == 0 {
+		return b
+	}
+
+	xs := []string{}
+	skip := 0
+
+	for _, s := range strings.Split(string(b), "\n") {
+		if m := headingPattern.FindStringSubmatch(s); m != nil {
+			if skip > 0 && len(m[1]) <= skip {
+				skip = 0
+			}
+
+			if g := groupHeadingPattern.FindStringSubmatch(s); g != nil && !api.MatchGroup(g[2], only, exclude) {
+				skip = len(g[1])
+			}
+		}
+
+		if skip == 0 {
+			xs = append(xs, s)
+		}
+	}
+
+	return []byte(strings.Join(xs, "\n"))
+}
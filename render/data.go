@@ -0,0 +1,122 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+// Data is the value HTML templates are executed with, custom templates can rely on its fields.
+// Names match api.API, so `{{.Title}}`, `{{range .ResourceGroups}}` and `{{.Meta "X-Team"}}`
+// work as before.
+type Data struct {
+	// Title and Description of the blueprint, Description is Markdown
+	Title       string
+	Description string
+
+	// Host is HOST metadata, e.g. `https://api.example.com`
+	Host string
+
+	// Metadata lists blueprint metadata in declaration order, including HOST and FORMAT
+	Metadata []api.Metadata
+
+	// ResourceGroups nest resources, their transitions and transactions in declaration order.
+	// Sections of groups and resources interleave them with Markdown copy written between.
+	ResourceGroups []api.ResourceGroup
+
+	// Resources and Transitions list every resource and transition of ResourceGroups, for
+	// templates not grouping them
+	Resources   []*api.Resource
+	Transitions []*api.Transition
+
+	// Routes list every transaction along with its group, resource and transition
+	Routes []api.Route
+
+	// DataStructures are named data structures, e.g. of `# Data Structures` section
+	DataStructures []api.DataStructure
+
+	// Authentication lists schemes of AUTH metadata, or those inferred from request headers
+	Authentication []api.AuthScheme
+
+	// Annotations are parser warnings and errors of the blueprint
+	Annotations []api.Annotation
+
+	// API is the blueprint itself
+	API *api.API
+}
+
+// NewData builds template data of a blueprint
+func NewData(b *api.API) *Data {
+	d := &Data{
+		Title:          b.Title,
+		Description:    b.Description,
+		Host:           b.Host(),
+		Metadata:       b.Metadata,
+		ResourceGroups: b.ResourceGroups,
+		DataStructures: b.DataStructures,
+		Authentication: b.AuthSchemes(),
+		Annotations:    b.Annotations,
+		API:            b,
+	}
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			d.Resources = append(d.Resources, r)
+			d.Transitions = append(d.Transitions, r.Transitions...)
+		}
+	}
+
+	for _, x := range api.Routes(b) {
+		if x.Transaction != nil {
+			d.Routes = append(d.Routes, x)
+		}
+	}
+
+	return d
+}
+
+// Meta returns value of metadata key, e.g. `{{.Meta "X-Team"}}`
+func (d *Data) Meta(key string) string {
+	return d.API.Meta(key)
+}
+
+// AuthHeaders returns headers carrying credentials, either well-known ones like Authorization or
+// those of Authentication schemes, e.g. `{{range $.AuthHeaders .Request.Headers}}`
+func (d *Data) AuthHeaders(hs []api.Header) []api.Header {
+	var xs []api.Header
+
+	for _, h := range hs {
+		if d.isAuthHeader(h.Key) {
+			xs = append(xs, h)
+		}
+	}
+
+	return xs
+}
+
+// PlainHeaders returns headers not returned by AuthHeaders
+func (d *Data) PlainHeaders(hs []api.Header) []api.Header {
+	var xs []api.Header
+
+	for _, h := range hs {
+		if !d.isAuthHeader(h.Key) {
+			xs = append(xs, h)
+		}
+	}
+
+	return xs
+}
+
+func (d *Data) isAuthHeader(key string) bool {
+	if api.IsAuthHeader(key) {
+		return true
+	}
+
+	for _, x := range d.Authentication {
+		if x.In == "header" && strings.EqualFold(x.Param, key) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewData(t *testing.T) {
+	b := newMessageAPI()
+	d := render.NewData(b)
+
+	assert.Equal(t, "Messages API", d.Title)
+	assert.Equal(t, "https://api.example.com", d.Host)
+	assert.Equal(t, "https://api.example.com", d.Meta("HOST"))
+	assert.Equal(t, b.ResourceGroups, d.ResourceGroups)
+	assert.Equal(t, b.ResourceGroups[0].Resources, d.Resources)
+	assert.Equal(t, b.ResourceGroups[0].Resources[0].Transitions, d.Transitions)
+	assert.Equal(t, b.Annotations, d.Annotations)
+	assert.Equal(t, b, d.API)
+
+	if assert.Len(t, d.Routes, 3) {
+		assert.Equal(t, "GET", d.Routes[0].Method)
+		assert.Equal(t, 404, d.Routes[1].StatusCode)
+		assert.Equal(t, 204, d.Routes[2].Transaction.Response.StatusCode)
+	}
+}
+
+func TestHTML_data(t *testing.T) {
+	var bf bytes.Buffer
+
+	b := newMessageAPI()
+	b.Annotations = []api.Annotation{{Description: "unexpected header", Classes: []string{"warning"}}}
+
+	tpl := `{{.Host}} {{.Meta "HOST"}}{{range .Routes}} {{.Method}} {{.StatusCode}}{{end}}{{range .Transitions}} {{.Method}}{{end}} {{.API.Title}}{{range .Annotations}} {{.Description}}{{end}}`
+
+	err := render.HTML(tpl, &bf, b)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.example.com https://api.example.com GET 200 GET 404 PUT 204 GET PUT Messages API unexpected header", bf.String())
+}
+
+func TestData_AuthHeaders(t *testing.T) {
+	b := newMessageAPI()
+	b.Metadata = append(b.Metadata, api.Metadata{Key: "AUTH", Value: "apiKey header X-Client-Key Issued per client"})
+
+	d := render.NewData(b)
+
+	assert.Equal(t, []api.AuthScheme{{Type: "apiKey", In: "header", Param: "X-Client-Key", Description: "Issued per client"}}, d.Authentication)
+
+	hs := []api.Header{
+		{Key: "Accept", Value: "application/json"},
+		{Key: "Authorization", Value: "Bearer abc"},
+		{Key: "x-client-key", Value: "secret"},
+	}
+
+	assert.Equal(t, hs[1:], d.AuthHeaders(hs))
+	assert.Equal(t, hs[:1], d.PlainHeaders(hs))
+	assert.Empty(t, d.AuthHeaders(nil))
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+const (
+	diagramRow     = 28
+	diagramBox     = 22
+	diagramMethodW = 70
+	diagramStatusX = 140
+	diagramStatusW = 50
+)
+
+var diagramColors = map[string]string{
+	"green":  "#21ba45",
+	"blue":   "#2185d0",
+	"teal":   "#00b5ad",
+	"violet": "#6435c9",
+	"red":    "#db2828",
+	"orange": "#f2711c",
+	"":       "#767676",
+}
+
+// Diagram draws transitions of resource r as inline SVG, every method box points to
+// status codes of its responses. Templates call it as `diagram`, it is disabled by default,
+// enable it for a template with Options{Funcs: template.FuncMap{"diagram": Diagram}}.
+func Diagram(r *api.Resource) template.HTML {
+	if r == nil || len(r.Transitions) == 0 {
+		return ""
+	}
+
+	var bf bytes.Buffer
+
+	rows := 0
+
+	for _, t := range r.Transitions {
+		rows += max(1, len(statusCodes(t)))
+	}
+
+	fmt.Fprintf(&bf, `<svg class="diagram" xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`, diagramStatusX+diagramStatusW+1, rows*diagramRow)
+
+	row := 0
+
+	for _, t := range r.Transitions {
+		ss := statusCodes(t)
+		n := max(1, len(ss))
+		mid := row*diagramRow + n*diagramRow/2
+
+		diagramBoxText(&bf, 0, mid, diagramMethodW, t.Method, colorize(t.Method))
+
+		for i, s := range ss {
+			y := (row+i)*diagramRow + diagramRow/2
+			x := diagramStatusX - 6
+
+			fmt.Fprintf(&bf, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#999"/>`, diagramMethodW, mid, x, y)
+			fmt.Fprintf(&bf, `<polygon points="%d,%d %d,%d %d,%d" fill="#999"/>`, x, y-4, diagramStatusX, y, x, y+4)
+
+			diagramBoxText(&bf, diagramStatusX, y, diagramStatusW, strconv.Itoa(s), colorize(s))
+		}
+
+		row += n
+	}
+
+	bf.WriteString(`</svg>`)
+
+	return template.HTML(bf.String())
+}
+
+func diagramBoxText(bf *bytes.Buffer, x, mid, w int, s, color string) {
+	fmt.Fprintf(bf, `<rect x="%d" y="%d" width="%d" height="%d" rx="3" fill="%s"/>`, x, mid-diagramBox/2, w, diagramBox, diagramColors[color])
+	fmt.Fprintf(bf, `<text x="%d" y="%d" fill="#fff" text-anchor="middle">%s</text>`, x+w/2, mid+4, template.HTMLEscapeString(s))
+}
+
+func statusCodes(t *api.Transition) []int {
+	var ss []int
+
+	for _, x := range t.Transactions {
+		if !containsInt(ss, x.Response.StatusCode) {
+			ss = append(ss, x.Response.StatusCode)
+		}
+	}
+
+	return ss
+}
+
+func containsInt(xs []int, n int) bool {
+	for _, x := range xs {
+		if x == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
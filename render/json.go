@@ -0,0 +1,145 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+// JSONOptions configures API Element JSON output
+type JSONOptions struct {
+	// Pretty indents output
+	Pretty bool
+	// Canonical sorts object keys recursively for byte-stable output
+	Canonical bool
+	// Only keeps resource groups of given titles, see api.MatchGroup
+	Only []string
+	// Exclude drops resource groups of given titles
+	Exclude []string
+}
+
+// JSON renders API Element JSON, e.g. of parser.LoadAsJSON, formatted according to opt
+func JSON(w io.Writer, b []byte, opt JSONOptions) error {
+	b, err := filterElementJSON(b, opt.Only, opt.Exclude)
+	if err != nil {
+		return err
+	}
+
+	if b, err = formatJSON(b, opt.Pretty, opt.Canonical); err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// formatJSON indents b when pretty, canonical output has object keys sorted recursively
+func formatJSON(b []byte, pretty, canonical bool) ([]byte, error) {
+	if canonical {
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+
+		var v interface{}
+		if err := d.Decode(&v); err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+
+		e := json.NewEncoder(&buf)
+		e.SetEscapeHTML(false)
+
+		if err := e.Encode(v); err != nil {
+			return nil, err
+		}
+
+		b = bytes.TrimSpace(buf.Bytes())
+	}
+
+	if pretty {
+		var buf bytes.Buffer
+
+		if err := json.Indent(&buf, b, "", "  "); err != nil {
+			return nil, err
+		}
+
+		b = buf.Bytes()
+	}
+
+	return b, nil
+}
+
+// filterElementJSON drops unmatched resource group categories from API Element JSON
+func filterElementJSON(b []byte, only, exclude []string) ([]byte, error) {
+	if len(only) == 0 && len(exclude) == 0 {
+		return b, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var v map[string]interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	for _, x := range elementContent(v) {
+		m, ok := x.(map[string]interface{})
+		if !ok || !hasElementClass(m, "api") {
+			continue
+		}
+
+		cs := []interface{}{}
+
+		for _, c := range elementContent(m) {
+			g, ok := c.(map[string]interface{})
+			if ok && hasElementClass(g, "resourceGroup") && !api.MatchGroup(elementTitle(g), only, exclude) {
+				continue
+			}
+
+			cs = append(cs, c)
+		}
+
+		m["content"] = cs
+	}
+
+	var buf bytes.Buffer
+
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func elementContent(m map[string]interface{}) []interface{} {
+	xs, _ := m["content"].([]interface{})
+	return xs
+}
+
+func elementMeta(m map[string]interface{}) map[string]interface{} {
+	z, _ := m["meta"].(map[string]interface{})
+	return z
+}
+
+func hasElementClass(m map[string]interface{}, class string) bool {
+	cs, _ := elementMeta(m)["classes"].([]interface{})
+
+	for _, c := range cs {
+		if c == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+func elementTitle(m map[string]interface{}) string {
+	s, _ := elementMeta(m)["title"].(string)
+	return s
+}
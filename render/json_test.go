@@ -0,0 +1,50 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bukalapak/snowboard/render"
+	"github.com/stretchr/testify/assert"
+)
+
+const elementJSON = `{"element": "parseResult", "content": [{"element": "category", "meta": {"classes": ["api"], "title": "API"}, "content": [` +
+	`{"element": "category", "meta": {"classes": ["resourceGroup"], "title": "Users"}, "content": []}, ` +
+	`{"element": "category", "meta": {"classes": ["resourceGroup"], "title": "Messages"}, "content": []}]}]}`
+
+func TestJSON(t *testing.T) {
+	var bf bytes.Buffer
+
+	assert.Nil(t, render.JSON(&bf, []byte(elementJSON), render.JSONOptions{}))
+	assert.Equal(t, elementJSON, bf.String())
+
+	bf.Reset()
+
+	assert.Nil(t, render.JSON(&bf, []byte(`{"b": 1, "a": "<x>"}`), render.JSONOptions{Pretty: true, Canonical: true}))
+	assert.Equal(t, "{\n  \"a\": \"<x>\",\n  \"b\": 1\n}", bf.String())
+}
+
+func TestJSON_filter(t *testing.T) {
+	var bf bytes.Buffer
+
+	assert.Nil(t, render.JSON(&bf, []byte(elementJSON), render.JSONOptions{Exclude: []string{"users"}}))
+	assert.NotContains(t, bf.String(), `"Users"`)
+	assert.Contains(t, bf.String(), `"title":"Messages"`)
+	assert.Contains(t, bf.String(), `"title":"API"`)
+
+	assert.NotNil(t, render.JSON(&bf, []byte(`{`), render.JSONOptions{Only: []string{"Users"}}))
+}
+
+func TestAPIB(t *testing.T) {
+	b := []byte("# API\n\n# Group Users\n\n## Users [/users]\n\n# Group Messages\n\n## Messages [/messages]\n\n# Data Structures\n\n## User (object)\n")
+
+	var bf bytes.Buffer
+
+	assert.Nil(t, render.APIB(&bf, b, render.APIBOptions{}))
+	assert.Equal(t, string(b), bf.String())
+
+	bf.Reset()
+
+	assert.Nil(t, render.APIB(&bf, b, render.APIBOptions{Only: []string{"messages"}}))
+	assert.Equal(t, "# API\n\n# Group Messages\n\n## Messages [/messages]\n\n# Data Structures\n\n## User (object)\n", bf.String())
+}
@@ -0,0 +1,154 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+// Markdown renders blueprint.API struct as GitHub Flavored Markdown document
+func Markdown(w io.Writer, b *api.API) error {
+	var bf bytes.Buffer
+
+	mdHeading(&bf, 1, b.Title)
+	mdParagraph(&bf, b.Description)
+
+	for _, g := range b.ResourceGroups {
+		mdHeading(&bf, 2, g.Title)
+		mdParagraph(&bf, g.Description)
+
+		for _, r := range g.Resources {
+			title := r.Title
+			if title == "" {
+				title = r.Href.Path
+			} else if r.Href.Path != "" {
+				title += " [" + r.Href.Path + "]"
+			}
+
+			mdHeading(&bf, 3, title)
+			mdParagraph(&bf, r.Description)
+			mdParameters(&bf, r.Href.Parameters)
+
+			for _, t := range r.Transitions {
+				mdTransition(&bf, r, t)
+			}
+		}
+	}
+
+	_, err := w.Write(bytes.TrimRight(bf.Bytes(), "\n"))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func mdTransition(bf *bytes.Buffer, r *api.Resource, t *api.Transition) {
+	p := r.Href.Path
+	if t.Href.Path != "" {
+		p = t.Href.Path
+	}
+
+	title := strings.TrimSpace(t.Method + " " + p)
+	if t.Title != "" {
+		title = t.Title + " [" + title + "]"
+	}
+
+	mdHeading(bf, 4, title)
+	mdParagraph(bf, t.Description)
+	mdParameters(bf, t.Href.Parameters)
+
+	for _, x := range t.Transactions {
+		if x.Request.Title != "" || x.Request.Body.Body != "" || len(x.Request.Headers) > 0 {
+			mdHeading(bf, 5, mdTitle("Request "+x.Request.Title, x.Request.Body.ContentType))
+			mdParagraph(bf, x.Request.Description)
+			mdHeaders(bf, x.Request.Headers)
+			mdCode(bf, x.Request.Body)
+			mdCode(bf, x.Request.Schema)
+		}
+
+		mdHeading(bf, 5, mdTitle(fmt.Sprintf("Response %d", x.Response.StatusCode), x.Response.Body.ContentType))
+		mdParagraph(bf, x.Response.Description)
+		mdHeaders(bf, x.Response.Headers)
+		mdCode(bf, x.Response.Body)
+		mdCode(bf, x.Response.Schema)
+	}
+}
+
+func mdTitle(s, contentType string) string {
+	s = strings.TrimSpace(s)
+
+	if contentType != "" {
+		s += " (" + contentType + ")"
+	}
+
+	return s
+}
+
+func mdHeading(bf *bytes.Buffer, level int, s string) {
+	if s == "" {
+		return
+	}
+
+	fmt.Fprintf(bf, "%s %s\n\n", strings.Repeat("#", level), s)
+}
+
+func mdParagraph(bf *bytes.Buffer, s string) {
+	if s = strings.TrimSpace(s); s == "" {
+		return
+	}
+
+	fmt.Fprintf(bf, "%s\n\n", s)
+}
+
+func mdParameters(bf *bytes.Buffer, ps []api.Parameter) {
+	if len(ps) == 0 {
+		return
+	}
+
+	bf.WriteString("| Name | Type | Required | Description | Example |\n")
+	bf.WriteString("| ---- | ---- | -------- | ----------- | ------- |\n")
+
+	for _, p := range ps {
+		req := "no"
+		if p.Required {
+			req = "yes"
+		}
+
+		fmt.Fprintf(bf, "| %s | %s | %s | %s | %s |\n", mdCell(p.Key), mdCell(p.Kind), req, mdCell(p.Description), mdCell(p.Value))
+	}
+
+	bf.WriteString("\n")
+}
+
+func mdHeaders(bf *bytes.Buffer, hs []api.Header) {
+	if len(hs) == 0 {
+		return
+	}
+
+	bf.WriteString("```http\n")
+
+	for _, h := range hs {
+		fmt.Fprintf(bf, "%s: %s\n", h.Key, h.Value)
+	}
+
+	bf.WriteString("```\n\n")
+}
+
+func mdCode(bf *bytes.Buffer, a api.Asset) {
+	s := strings.Trim(a.Body, "\n")
+	if s == "" {
+		return
+	}
+
+	fmt.Fprintf(bf, "```%s\n%s\n```\n\n", alias(a.ContentType), s)
+}
+
+func mdCell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,33 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bukalapak/snowboard/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdown(t *testing.T) {
+	var bf bytes.Buffer
+
+	err := render.Markdown(&bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, "# Messages API\n\n"+
+		"## Messages\n\n"+
+		"### Message [/messages/{id}{?fields}]\n\n"+
+		"| Name | Type | Required | Description | Example |\n"+
+		"| ---- | ---- | -------- | ----------- | ------- |\n"+
+		"| id | number | yes |  | 1 |\n"+
+		"| fields | enum[string] | no |  |  |\n\n"+
+		"#### Retrieve a Message [GET /messages/{id}{?fields}]\n\n"+
+		"##### Response 200 (application/json)\n\n"+
+		"```http\nX-Rate-Limit: 10\n```\n\n"+
+		"```json\n{\"id\": 1, \"body\": \"Hello\"}\n```\n\n"+
+		"```json\n{\"$schema\": \"http://json-schema.org/draft-04/schema#\", \"type\": \"object\"}\n```\n\n"+
+		"##### Response 404\n\n"+
+		"#### PUT /messages/{id}{?fields}\n\n"+
+		"##### Request (application/json)\n\n"+
+		"```json\n{\"body\": \"Hi\"}\n```\n\n"+
+		"##### Response 204\n", bf.String())
+}
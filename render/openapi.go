@@ -0,0 +1,590 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	uriQueryPattern    = regexp.MustCompile(`\{[?&]([^}]+)\}`)
+	uriVariablePattern = regexp.MustCompile(`\{[+#./;]?([^}]+)\}`)
+)
+
+type openAPI struct {
+	OpenAPI    string                                  `yaml:"openapi"`
+	Info       openAPIInfo                             `yaml:"info"`
+	Servers    []openAPIServer                         `yaml:"servers,omitempty"`
+	Tags       []openAPITag                            `yaml:"tags,omitempty"`
+	Paths      map[string]map[string]*openAPIOperation `yaml:"paths"`
+	Components openAPIComponents                       `yaml:"components,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description,omitempty"`
+	Version     string `yaml:"version"`
+}
+
+type openAPIServer struct {
+	URL string `yaml:"url"`
+}
+
+type openAPITag struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]interface{} `yaml:"schemas,omitempty"`
+}
+
+type openAPIOperation struct {
+	Summary     string                      `yaml:"summary,omitempty"`
+	Description string                      `yaml:"description,omitempty"`
+	OperationID string                      `yaml:"operationId,omitempty"`
+	Tags        []string                    `yaml:"tags,omitempty"`
+	Parameters  []openAPIParameter          `yaml:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `yaml:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string                 `yaml:"name"`
+	In          string                 `yaml:"in"`
+	Description string                 `yaml:"description,omitempty"`
+	Required    bool                   `yaml:"required,omitempty"`
+	Schema      map[string]interface{} `yaml:"schema"`
+	Example     interface{}            `yaml:"example,omitempty"`
+}
+
+type openAPIRequestBody struct {
+	Description string                       `yaml:"description,omitempty"`
+	Content     map[string]*openAPIMediaType `yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `yaml:"description"`
+	Headers     map[string]*openAPIHeader    `yaml:"headers,omitempty"`
+	Content     map[string]*openAPIMediaType `yaml:"content,omitempty"`
+}
+
+type openAPIHeader struct {
+	Schema  map[string]interface{} `yaml:"schema"`
+	Example string                 `yaml:"example,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema  interface{} `yaml:"schema,omitempty"`
+	Example interface{} `yaml:"example,omitempty"`
+}
+
+// OpenAPI renders blueprint.API struct as OpenAPI 3.0 YAML document
+func OpenAPI(w io.Writer, b *api.API) error {
+	z, err := yaml.Marshal(newOpenAPI(b))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(z)
+	return err
+}
+
+func newOpenAPI(b *api.API) *openAPI {
+	o := &openAPI{
+		OpenAPI: "3.0.0",
+		Info: openAPIInfo{
+			Title:       b.Title,
+			Description: b.Description,
+			Version:     openAPIVersion(b),
+		},
+		Paths: map[string]map[string]*openAPIOperation{},
+	}
+
+	if h := b.Host(); h != "" {
+		o.Servers = []openAPIServer{{URL: h}}
+	}
+
+	for _, g := range b.ResourceGroups {
+		if g.Title != "" {
+			o.Tags = append(o.Tags, openAPITag{Name: g.Title, Description: g.Description})
+		}
+
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				if t.Method == "" {
+					continue
+				}
+
+				p, params := openAPIPath(r, t)
+
+				if _, ok := o.Paths[p]; !ok {
+					o.Paths[p] = map[string]*openAPIOperation{}
+				}
+
+				op := newOpenAPIOperation(t, params)
+
+				if g.Title != "" {
+					op.Tags = []string{g.Title}
+				}
+
+				m := strings.ToLower(t.Method)
+				if x, ok := o.Paths[p][m]; ok {
+					mergeOpenAPIOperation(x, op)
+					continue
+				}
+
+				o.Paths[p][m] = op
+			}
+		}
+	}
+
+	if len(b.DataStructures) > 0 {
+		o.Components.Schemas = map[string]interface{}{}
+		names := map[string]bool{}
+
+		for _, d := range b.DataStructures {
+			names[d.Name] = true
+		}
+
+		for _, d := range b.DataStructures {
+			o.Components.Schemas[d.Name] = openAPIDataStructure(d, names)
+		}
+	}
+
+	return o
+}
+
+func openAPIVersion(b *api.API) string {
+	for _, m := range b.Metadata {
+		if strings.EqualFold(m.Key, "VERSION") {
+			return m.Value
+		}
+	}
+
+	return "1.0.0"
+}
+
+func openAPIPath(r *api.Resource, t *api.Transition) (string, []openAPIParameter) {
+	href := r.Href.Path
+	if t.Href.Path != "" {
+		href = t.Href.Path
+	}
+
+	p := uriQueryPattern.ReplaceAllString(href, "")
+	if i := strings.Index(p, "?"); i >= 0 {
+		p = p[:i]
+	}
+
+	names := []string{}
+	inPath := map[string]bool{}
+
+	p = uriVariablePattern.ReplaceAllStringFunc(p, func(s string) string {
+		var xs []string
+
+		for _, n := range strings.Split(uriVariablePattern.FindStringSubmatch(s)[1], ",") {
+			n = strings.TrimSuffix(n, "*")
+			names = append(names, n)
+			inPath[n] = true
+			xs = append(xs, "{"+n+"}")
+		}
+
+		return strings.Join(xs, "/")
+	})
+
+	if p == "" {
+		p = "/"
+	}
+
+	seen := map[string]bool{}
+	params := []openAPIParameter{}
+
+	xs := []api.Parameter{}
+	xs = append(xs, t.Href.Parameters...)
+	xs = append(xs, r.Href.Parameters...)
+
+	for _, x := range xs {
+		if seen[x.Key] {
+			continue
+		}
+
+		seen[x.Key] = true
+
+		n := openAPIParameter{
+			Name:        x.Key,
+			In:          "query",
+			Description: x.Description,
+			Required:    x.Required,
+			Schema:      openAPIParameterSchema(x),
+			Example:     openAPIExample(x.Kind, x.Value),
+		}
+
+		if inPath[x.Key] {
+			n.In = "path"
+			n.Required = true
+		}
+
+		params = append(params, n)
+	}
+
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			params = append(params, openAPIParameter{Name: n, In: "path", Required: true, Schema: map[string]interface{}{"type": "string"}})
+		}
+	}
+
+	return p, params
+}
+
+func openAPIParameterSchema(x api.Parameter) map[string]interface{} {
+	s := map[string]interface{}{"type": openAPIType(x.Kind)}
+
+	if strings.HasPrefix(x.Kind, "enum[") {
+		s["type"] = openAPIType(strings.TrimSuffix(strings.TrimPrefix(x.Kind, "enum["), "]"))
+		s["enum"] = x.Members
+	}
+
+	if x.Default != "" {
+		s["default"] = x.Default
+	}
+
+	return s
+}
+
+func openAPIType(kind string) string {
+	switch kind {
+	case "number", "boolean", "object", "array":
+		return kind
+	}
+
+	return "string"
+}
+
+func openAPIExample(kind, value string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	switch kind {
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+
+	return value
+}
+
+func newOpenAPIOperation(t *api.Transition, params []openAPIParameter) *openAPIOperation {
+	op := &openAPIOperation{
+		Summary:     t.Title,
+		Description: t.Description,
+		OperationID: t.Permalink,
+		Parameters:  params,
+		Responses:   map[string]*openAPIResponse{},
+	}
+
+	for _, x := range t.Transactions {
+		if ct := x.Request.Body.ContentType; x.Request.Body.Body != "" || x.Request.Schema.Body != "" {
+			if ct == "" {
+				ct = headerValue(x.Request.Headers, "Content-Type")
+			}
+
+			if ct == "" {
+				ct = "text/plain"
+			}
+
+			if op.RequestBody == nil {
+				op.RequestBody = &openAPIRequestBody{
+					Description: x.Request.Description,
+					Content:     map[string]*openAPIMediaType{},
+				}
+			}
+
+			if _, ok := op.RequestBody.Content[ct]; !ok {
+				op.RequestBody.Content[ct] = openAPIMedia(x.Request.Body, x.Request.Schema)
+			}
+		}
+
+		code := strconv.Itoa(x.Response.StatusCode)
+		if x.Response.StatusCode == 0 {
+			code = "default"
+		}
+
+		res, ok := op.Responses[code]
+		if !ok {
+			res = &openAPIResponse{Description: x.Response.Description}
+
+			if res.Description == "" {
+				res.Description = http.StatusText(x.Response.StatusCode)
+			}
+
+			if res.Description == "" {
+				res.Description = "Response"
+			}
+
+			op.Responses[code] = res
+		}
+
+		for _, h := range x.Response.Headers {
+			if strings.EqualFold(h.Key, "Content-Type") {
+				continue
+			}
+
+			if res.Headers == nil {
+				res.Headers = map[string]*openAPIHeader{}
+			}
+
+			res.Headers[h.Key] = &openAPIHeader{
+				Schema:  map[string]interface{}{"type": "string"},
+				Example: h.Value,
+			}
+		}
+
+		if x.Response.Body.Body == "" && x.Response.Schema.Body == "" {
+			continue
+		}
+
+		ct := x.Response.Body.ContentType
+		if ct == "" {
+			ct = headerValue(x.Response.Headers, "Content-Type")
+		}
+
+		if ct == "" {
+			ct = "text/plain"
+		}
+
+		if res.Content == nil {
+			res.Content = map[string]*openAPIMediaType{}
+		}
+
+		if _, ok := res.Content[ct]; !ok {
+			res.Content[ct] = openAPIMedia(x.Response.Body, x.Response.Schema)
+		}
+	}
+
+	return op
+}
+
+// mergeOpenAPIOperation adds tags, parameters, request body and responses of x to op, for transitions
+// whose paths only differ in query, e.g. `/messages{?page}` and `/messages{?q}`. Query parameters are
+// required only when both of them require it, the first summary, description and ID win.
+func mergeOpenAPIOperation(op, x *openAPIOperation) {
+	if op.Summary == "" {
+		op.Summary = x.Summary
+	}
+
+	if op.Description == "" {
+		op.Description = x.Description
+	}
+
+	if op.OperationID == "" {
+		op.OperationID = x.OperationID
+	}
+
+	for _, t := range x.Tags {
+		if !containsString(op.Tags, t) {
+			op.Tags = append(op.Tags, t)
+		}
+	}
+
+	params := map[string]openAPIParameter{}
+	for _, n := range x.Parameters {
+		params[n.In+" "+n.Name] = n
+	}
+
+	seen := map[string]bool{}
+
+	for i, n := range op.Parameters {
+		k := n.In + " " + n.Name
+		seen[k] = true
+
+		if z, ok := params[k]; !ok || !z.Required {
+			op.Parameters[i].Required = n.In == "path"
+		}
+	}
+
+	for _, n := range x.Parameters {
+		if k := n.In + " " + n.Name; !seen[k] {
+			n.Required = n.In == "path"
+			op.Parameters = append(op.Parameters, n)
+		}
+	}
+
+	if op.RequestBody == nil {
+		op.RequestBody = x.RequestBody
+	} else if x.RequestBody != nil {
+		for ct, m := range x.RequestBody.Content {
+			if _, ok := op.RequestBody.Content[ct]; !ok {
+				op.RequestBody.Content[ct] = m
+			}
+		}
+	}
+
+	for code, r := range x.Responses {
+		res, ok := op.Responses[code]
+		if !ok {
+			op.Responses[code] = r
+			continue
+		}
+
+		for k, h := range r.Headers {
+			if res.Headers == nil {
+				res.Headers = map[string]*openAPIHeader{}
+			}
+
+			if _, ok := res.Headers[k]; !ok {
+				res.Headers[k] = h
+			}
+		}
+
+		for ct, m := range r.Content {
+			if res.Content == nil {
+				res.Content = map[string]*openAPIMediaType{}
+			}
+
+			if _, ok := res.Content[ct]; !ok {
+				res.Content[ct] = m
+			}
+		}
+	}
+}
+
+func openAPIMedia(body, schema api.Asset) *openAPIMediaType {
+	m := &openAPIMediaType{}
+
+	if schema.Body != "" {
+		var s map[string]interface{}
+
+		if err := json.Unmarshal([]byte(schema.Body), &s); err == nil {
+			delete(s, "$schema")
+			m.Schema = s
+		}
+	}
+
+	if body.Body != "" {
+		var v interface{}
+
+		if strings.Contains(body.ContentType, "json") && json.Unmarshal([]byte(body.Body), &v) == nil {
+			m.Example = v
+		} else {
+			m.Example = body.Body
+		}
+	}
+
+	return m
+}
+
+func openAPIDataStructure(d api.DataStructure, names map[string]bool) map[string]interface{} {
+	s := openAPISchema(api.Member{Kind: d.Kind, Members: d.Members}, names)
+
+	if d.Description != "" {
+		s["description"] = d.Description
+	}
+
+	return s
+}
+
+// openAPISchema converts MSON member into schema, named types are referenced only when names declares
+// them, other ones are plain objects so the document has no dangling reference
+func openAPISchema(m api.Member, names map[string]bool) map[string]interface{} {
+	s := map[string]interface{}{}
+
+	switch m.Kind {
+	case "string", "number", "boolean":
+		s["type"] = m.Kind
+	case "object":
+		s["type"] = "object"
+
+		props := map[string]interface{}{}
+		required := []string{}
+		mixins := []interface{}{}
+
+		for _, n := range m.Members {
+			if n.Key == "" {
+				mixins = append(mixins, openAPISchema(n, names))
+				continue
+			}
+
+			props[n.Key] = openAPISchema(n, names)
+
+			if n.Required {
+				required = append(required, n.Key)
+			}
+		}
+
+		if len(props) > 0 {
+			s["properties"] = props
+		}
+
+		if len(required) > 0 {
+			s["required"] = required
+		}
+
+		if len(mixins) > 0 {
+			s = map[string]interface{}{"allOf": append(mixins, s)}
+		}
+	case "array":
+		s["type"] = "array"
+
+		if len(m.Members) > 0 {
+			s["items"] = openAPISchema(m.Members[0], names)
+		}
+	case "enum":
+		values := []interface{}{}
+
+		for _, n := range m.Members {
+			values = append(values, n.Value)
+		}
+
+		s["enum"] = values
+	case "":
+	default:
+		if names[m.Kind] {
+			s["$ref"] = "#/components/schemas/" + m.Kind
+		} else {
+			s["type"] = "object"
+		}
+	}
+
+	if m.Description != "" && s["$ref"] == nil {
+		s["description"] = m.Description
+	}
+
+	if m.Value != "" && s["type"] != nil {
+		s["example"] = openAPIExample(m.Kind, m.Value)
+	}
+
+	return s
+}
+
+func headerValue(hs []api.Header, key string) string {
+	for _, h := range hs {
+		if strings.EqualFold(h.Key, key) {
+			return h.Value
+		}
+	}
+
+	return ""
+}
+
+func containsString(xs []string, s string) bool {
+	for _, x := range xs {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
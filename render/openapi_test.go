@@ -0,0 +1,395 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	snowboard "github.com/bukalapak/snowboard/parser"
+	"github.com/bukalapak/snowboard/render"
+	"github.com/bukalapak/snowboard/schema"
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// openAPISchema is the subset of OpenAPI 3.0 schema covering what render.OpenAPI writes
+const openAPISchema = `{
+	"type": "object",
+	"required": ["openapi", "info", "paths"],
+	"additionalProperties": false,
+	"properties": {
+		"openapi": {"type": "string", "pattern": "^3\\.0\\.\\d+$"},
+		"info": {
+			"type": "object",
+			"required": ["title", "version"],
+			"properties": {"title": {"type": "string"}, "description": {"type": "string"}, "version": {"type": "string"}}
+		},
+		"servers": {"type": "array", "items": {"type": "object", "required": ["url"], "properties": {"url": {"type": "string"}}}},
+		"tags": {"type": "array", "items": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}},
+		"paths": {"type": "object", "additionalProperties": {"$ref": "#/definitions/pathItem"}},
+		"components": {
+			"type": "object",
+			"properties": {"schemas": {"type": "object", "additionalProperties": {"$ref": "#/definitions/schema"}}}
+		}
+	},
+	"definitions": {
+		"pathItem": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"get": {"$ref": "#/definitions/operation"},
+				"put": {"$ref": "#/definitions/operation"},
+				"post": {"$ref": "#/definitions/operation"},
+				"delete": {"$ref": "#/definitions/operation"},
+				"options": {"$ref": "#/definitions/operation"},
+				"head": {"$ref": "#/definitions/operation"},
+				"patch": {"$ref": "#/definitions/operation"},
+				"trace": {"$ref": "#/definitions/operation"}
+			}
+		},
+		"operation": {
+			"type": "object",
+			"required": ["responses"],
+			"properties": {
+				"summary": {"type": "string"},
+				"description": {"type": "string"},
+				"operationId": {"type": "string"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"parameters": {"type": "array", "items": {"$ref": "#/definitions/parameter"}},
+				"requestBody": {"type": "object", "required": ["content"], "properties": {"content": {"$ref": "#/definitions/content"}}},
+				"responses": {"type": "object", "minProperties": 1, "additionalProperties": {"$ref": "#/definitions/response"}}
+			}
+		},
+		"parameter": {
+			"type": "object",
+			"required": ["name", "in", "schema"],
+			"properties": {
+				"name": {"type": "string"},
+				"in": {"enum": ["path", "query", "header", "cookie"]},
+				"required": {"type": "boolean"},
+				"schema": {"$ref": "#/definitions/schema"}
+			}
+		},
+		"response": {
+			"type": "object",
+			"required": ["description"],
+			"properties": {
+				"description": {"type": "string"},
+				"headers": {"type": "object", "additionalProperties": {"type": "object", "required": ["schema"]}},
+				"content": {"$ref": "#/definitions/content"}
+			}
+		},
+		"content": {
+			"type": "object",
+			"additionalProperties": {"type": "object", "properties": {"schema": {"$ref": "#/definitions/schema"}}}
+		},
+		"schema": {
+			"type": "object",
+			"properties": {
+				"$ref": {"type": "string", "pattern": "^#/components/schemas/"},
+				"type": {"enum": ["string", "number", "integer", "boolean", "object", "array"]},
+				"properties": {"type": "object", "additionalProperties": {"$ref": "#/definitions/schema"}},
+				"items": {"$ref": "#/definitions/schema"},
+				"allOf": {"type": "array", "items": {"$ref": "#/definitions/schema"}},
+				"required": {"type": "array", "items": {"type": "string"}},
+				"enum": {"type": "array"}
+			}
+		}
+	}
+}`
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// validateOpenAPI validates document against openAPISchema, then checks what the schema can not:
+// references resolve, path templates declare their parameters and operation IDs are unique
+func validateOpenAPI(t *testing.T, name string, b []byte) {
+	var v interface{}
+
+	if !assert.Nil(t, yaml.Unmarshal(b, &v), name) {
+		return
+	}
+
+	v = jsonValue(v)
+
+	z, err := json.Marshal(v)
+	assert.Nil(t, err, name)
+
+	errs, err := schema.Validate([]byte(openAPISchema), z)
+	assert.Nil(t, err, name)
+	assert.Empty(t, errs, name)
+
+	o, _ := v.(map[string]interface{})
+	components, _ := o["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+
+	for _, ref := range openAPIRefs(v) {
+		_, ok := schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+		assert.True(t, ok, "%s: dangling $ref %s", name, ref)
+	}
+
+	ids := map[string]bool{}
+	paths, _ := o["paths"].(map[string]interface{})
+
+	for p, item := range paths {
+		ops, _ := item.(map[string]interface{})
+
+		for method, x := range ops {
+			op, _ := x.(map[string]interface{})
+
+			if id, ok := op["operationId"].(string); ok {
+				assert.False(t, ids[id], "%s: duplicate operationId %s", name, id)
+				ids[id] = true
+			}
+
+			declared := map[string]bool{}
+			params, _ := op["parameters"].([]interface{})
+
+			for _, x := range params {
+				if n, _ := x.(map[string]interface{}); n["in"] == "path" {
+					assert.Equal(t, true, n["required"], "%s: %s %s path parameter %v must be required", name, method, p, n["name"])
+					declared[fmt.Sprint(n["name"])] = true
+				}
+			}
+
+			for _, m := range pathParamPattern.FindAllStringSubmatch(p, -1) {
+				assert.True(t, declared[m[1]], "%s: %s %s does not declare path parameter %s", name, method, p, m[1])
+			}
+		}
+	}
+}
+
+// jsonValue converts YAML maps into JSON objects
+func jsonValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+
+		for k, v := range x {
+			m[fmt.Sprint(k)] = jsonValue(v)
+		}
+
+		return m
+	case []interface{}:
+		for i := range x {
+			x[i] = jsonValue(x[i])
+		}
+	}
+
+	return v
+}
+
+func openAPIRefs(v interface{}) []string {
+	var xs []string
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, v := range x {
+			if s, ok := v.(string); ok && k == "$ref" {
+				xs = append(xs, s)
+				continue
+			}
+
+			xs = append(xs, openAPIRefs(v)...)
+		}
+	case []interface{}:
+		for _, v := range x {
+			xs = append(xs, openAPIRefs(v)...)
+		}
+	}
+
+	return xs
+}
+
+func newMessageAPI() *api.API {
+	return &api.API{
+		Title:    "Messages API",
+		Metadata: []api.Metadata{{Key: "HOST", Value: "https://api.example.com"}},
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Title: "Messages",
+				Resources: []*api.Resource{
+					{
+						Title: "Message",
+						Href: api.Href{
+							Path: "/messages/{id}{?fields}",
+							Parameters: []api.Parameter{
+								{Key: "id", Kind: "number", Required: true, Value: "1"},
+								{Key: "fields", Kind: "enum[string]", Members: []string{"id", "body"}},
+							},
+						},
+						Transitions: []*api.Transition{
+							{
+								Title:     "Retrieve a Message",
+								Method:    "GET",
+								Permalink: "messages-message-retrieve-a-message",
+								Transactions: []api.Transaction{
+									{
+										Request: api.Request{Method: "GET"},
+										Response: api.Response{
+											StatusCode: 200,
+											Headers:    []api.Header{{Key: "X-Rate-Limit", Value: "10"}},
+											Body:       api.Asset{ContentType: "application/json", Body: `{"id": 1, "body": "Hello"}`},
+											Schema:     api.Asset{ContentType: "application/schema+json", Body: `{"$schema": "http://json-schema.org/draft-04/schema#", "type": "object"}`},
+										},
+									},
+									{
+										Request:  api.Request{Method: "GET"},
+										Response: api.Response{StatusCode: 404},
+									},
+								},
+							},
+							{
+								Method: "PUT",
+								Transactions: []api.Transaction{
+									{
+										Request: api.Request{
+											Method: "PUT",
+											Body:   api.Asset{ContentType: "application/json", Body: `{"body": "Hi"}`},
+										},
+										Response: api.Response{StatusCode: 204},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		DataStructures: []api.DataStructure{
+			{
+				Name: "Message",
+				Kind: "object",
+				Members: []api.Member{
+					{Key: "id", Kind: "number", Value: "1", Required: true},
+					{Key: "author", Kind: "User"},
+					{Key: "tags", Kind: "array", Members: []api.Member{{Kind: "string"}}},
+				},
+			},
+		},
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	var bf bytes.Buffer
+
+	err := render.OpenAPI(&bf, newMessageAPI())
+	assert.Nil(t, err)
+
+	s := bf.String()
+	assert.True(t, strings.HasPrefix(s, "openapi: 3.0.0\ninfo:\n  title: Messages API\n  version: 1.0.0\n"))
+	assert.Contains(t, s, "servers:\n- url: https://api.example.com\ntags:\n- name: Messages\n")
+	assert.Contains(t, s, "paths:\n  /messages/{id}:\n    get:\n      summary: Retrieve a Message\n")
+	assert.Contains(t, s, "      operationId: messages-message-retrieve-a-message\n")
+	assert.Contains(t, s, "      - name: id\n        in: path\n        required: true\n        schema:\n          type: number\n        example: 1\n")
+	assert.Contains(t, s, "      - name: fields\n        in: query\n        schema:\n          enum:\n          - id\n          - body\n")
+	assert.Contains(t, s, "        \"200\":\n          description: OK\n          headers:\n            X-Rate-Limit:\n")
+	assert.Contains(t, s, "            application/json:\n              schema:\n                type: object\n")
+	assert.Contains(t, s, "        \"404\":\n          description: Not Found\n")
+	assert.NotContains(t, s, "$schema")
+	assert.Contains(t, s, "    put:\n")
+	assert.Contains(t, s, "      requestBody:\n        content:\n          application/json:\n            example:\n              body: Hi\n")
+	assert.Contains(t, s, "components:\n  schemas:\n    Message:\n      properties:\n")
+	assert.Contains(t, s, "        author:\n          type: object\n")
+	assert.Contains(t, s, "        tags:\n          items:\n            type: string\n          type: array\n")
+	assert.Contains(t, s, "      required:\n      - id\n")
+
+	validateOpenAPI(t, "Messages API", bf.Bytes())
+}
+
+func TestOpenAPI_dataStructureRef(t *testing.T) {
+	var bf bytes.Buffer
+
+	b := newMessageAPI()
+	b.DataStructures = append(b.DataStructures, api.DataStructure{Name: "User", Kind: "object", Members: []api.Member{{Key: "name", Kind: "string"}}})
+
+	err := render.OpenAPI(&bf, b)
+	assert.Nil(t, err)
+	assert.Contains(t, bf.String(), "        author:\n          $ref: '#/components/schemas/User'\n")
+	assert.Contains(t, bf.String(), "    User:\n      properties:\n        name:\n          type: string\n")
+
+	validateOpenAPI(t, "Messages API", bf.Bytes())
+}
+
+func TestOpenAPI_sharedPath(t *testing.T) {
+	var bf bytes.Buffer
+
+	b := &api.API{
+		Title: "Messages API",
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Title: "Messages",
+				Resources: []*api.Resource{
+					{
+						Href: api.Href{Path: "/messages{?page}", Parameters: []api.Parameter{{Key: "page", Kind: "number"}}},
+						Transitions: []*api.Transition{
+							{
+								Title:        "List Messages",
+								Method:       "GET",
+								Permalink:    "messages-list-messages",
+								Transactions: []api.Transaction{{Response: api.Response{StatusCode: 200}}},
+							},
+						},
+					},
+					{
+						Href: api.Href{Path: "/messages{?q}", Parameters: []api.Parameter{{Key: "q", Kind: "string", Required: true}}},
+						Transitions: []*api.Transition{
+							{
+								Title:     "Search Messages",
+								Method:    "GET",
+								Permalink: "messages-search-messages",
+								Transactions: []api.Transaction{
+									{Response: api.Response{StatusCode: 200, Body: api.Asset{ContentType: "application/json", Body: `[]`}}},
+									{Response: api.Response{StatusCode: 400}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := render.OpenAPI(&bf, b)
+	assert.Nil(t, err)
+
+	s := bf.String()
+	assert.Contains(t, s, "  /messages:\n    get:\n      summary: List Messages\n      operationId: messages-list-messages\n")
+	assert.Contains(t, s, "      - name: page\n        in: query\n        schema:\n          type: number\n      - name: q\n        in: query\n        schema:\n          type: string\n")
+	assert.Contains(t, s, "        \"200\":\n          description: OK\n          content:\n            application/json:\n")
+	assert.Contains(t, s, "        \"400\":\n          description: Bad Request\n")
+	assert.NotContains(t, s, "Search Messages")
+
+	validateOpenAPI(t, "Messages API", bf.Bytes())
+}
+
+func TestOpenAPI_blueprints(t *testing.T) {
+	fs, err := filepath.Glob("../fixtures/api-blueprint/examples/*.md")
+	assert.Nil(t, err)
+
+	fs = append(fs, "../adapter/drafter/ext/drafter/features/fixtures/blueprint.apib", "../fixtures/examples/enum.apib", "../fixtures/partials/API.apib", "../fixtures/seeds/API.apib")
+
+	for _, name := range fs {
+		b, err := snowboard.Load(name)
+		if !assert.Nil(t, err, name) {
+			continue
+		}
+
+		var bf bytes.Buffer
+
+		assert.Nil(t, render.OpenAPI(&bf, b), name)
+		validateOpenAPI(t, name, bf.Bytes())
+	}
+}
+
+func TestOpenAPI_empty(t *testing.T) {
+	var bf bytes.Buffer
+
+	err := render.OpenAPI(&bf, &api.API{Title: "API", Metadata: []api.Metadata{{Key: "VERSION", Value: "2.1"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "openapi: 3.0.0\ninfo:\n  title: API\n  version: \"2.1\"\npaths: {}\n", bf.String())
+}
@@ -0,0 +1,241 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []*postmanItem    `json:"item"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Item        []*postmanItem     `json:"item,omitempty"`
+	Request     *postmanRequest    `json:"request,omitempty"`
+	Response    []*postmanResponse `json:"response,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string          `json:"method"`
+	Header      []postmanHeader `json:"header"`
+	Body        *postmanBody    `json:"body,omitempty"`
+	URL         postmanURL      `json:"url"`
+	Description string          `json:"description,omitempty"`
+}
+
+type postmanResponse struct {
+	Name   string          `json:"name"`
+	Status string          `json:"status,omitempty"`
+	Code   int             `json:"code"`
+	Header []postmanHeader `json:"header"`
+	Body   string          `json:"body,omitempty"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanVariable `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// Postman renders blueprint.API struct as Postman Collection v2.1 JSON
+func Postman(w io.Writer, b *api.API) error {
+	c := &postmanCollection{
+		Info: postmanInfo{
+			Name:        b.Title,
+			Description: b.Description,
+			Schema:      postmanSchema,
+		},
+		Item:     []*postmanItem{},
+		Variable: []postmanVariable{{Key: "baseUrl", Value: strings.TrimSuffix(b.Host(), "/")}},
+	}
+
+	for _, g := range b.ResourceGroups {
+		items := []*postmanItem{}
+
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				items = append(items, newPostmanItem(r, t))
+			}
+		}
+
+		if g.Title == "" {
+			c.Item = append(c.Item, items...)
+			continue
+		}
+
+		c.Item = append(c.Item, &postmanItem{
+			Name:        g.Title,
+			Description: g.Description,
+			Item:        items,
+		})
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(c)
+}
+
+func newPostmanItem(r *api.Resource, t *api.Transition) *postmanItem {
+	n := &postmanItem{
+		Name:        t.Title,
+		Description: t.Description,
+		Request: &postmanRequest{
+			Method: t.Method,
+			Header: []postmanHeader{},
+			URL:    newPostmanURL(r, t),
+		},
+	}
+
+	if n.Name == "" {
+		n.Name = strings.TrimSpace(t.Method + " " + n.Request.URL.Raw)
+	}
+
+	var found bool
+
+	for _, x := range t.Transactions {
+		if !found && x.Request.Method != "" {
+			found = true
+			n.Request.Description = x.Request.Description
+			n.Request.Header = postmanHeaders(x.Request.Headers)
+
+			if x.Request.Body.Body != "" {
+				n.Request.Body = &postmanBody{Mode: "raw", Raw: x.Request.Body.Body}
+
+				if alias(x.Request.Body.ContentType) == "json" {
+					n.Request.Body.Options = map[string]interface{}{"raw": map[string]string{"language": "json"}}
+				}
+			}
+		}
+
+		name := x.Request.Title
+		if name == "" {
+			name = http.StatusText(x.Response.StatusCode)
+		}
+
+		n.Response = append(n.Response, &postmanResponse{
+			Name:   name,
+			Status: http.StatusText(x.Response.StatusCode),
+			Code:   x.Response.StatusCode,
+			Header: postmanHeaders(x.Response.Headers),
+			Body:   x.Response.Body.Body,
+		})
+	}
+
+	return n
+}
+
+func newPostmanURL(r *api.Resource, t *api.Transition) postmanURL {
+	href := r.Href.Path
+	if t.Href.Path != "" {
+		href = t.Href.Path
+	}
+
+	params := map[string]api.Parameter{}
+
+	for _, x := range r.Href.Parameters {
+		params[x.Key] = x
+	}
+
+	for _, x := range t.Href.Parameters {
+		params[x.Key] = x
+	}
+
+	u := postmanURL{Host: []string{"{{baseUrl}}"}, Path: []string{}}
+
+	for _, m := range uriQueryPattern.FindAllStringSubmatch(href, -1) {
+		for _, k := range strings.Split(m[1], ",") {
+			x := params[k]
+			u.Query = append(u.Query, postmanVariable{
+				Key:         k,
+				Value:       x.Value,
+				Description: x.Description,
+				Disabled:    !x.Required,
+			})
+		}
+	}
+
+	p := uriQueryPattern.ReplaceAllString(href, "")
+	p = uriVariablePattern.ReplaceAllStringFunc(p, func(s string) string {
+		var xs []string
+
+		for _, k := range strings.Split(uriVariablePattern.FindStringSubmatch(s)[1], ",") {
+			k = strings.TrimSuffix(k, "*")
+			x := params[k]
+			u.Variable = append(u.Variable, postmanVariable{Key: k, Value: x.Value, Description: x.Description})
+			xs = append(xs, ":"+k)
+		}
+
+		return strings.Join(xs, "/")
+	})
+
+	for _, s := range strings.Split(strings.Trim(p, "/"), "/") {
+		if s != "" {
+			u.Path = append(u.Path, s)
+		}
+	}
+
+	u.Raw = "{{baseUrl}}/" + strings.Join(u.Path, "/")
+
+	if len(u.Query) > 0 {
+		xs := []string{}
+
+		for _, q := range u.Query {
+			if !q.Disabled {
+				xs = append(xs, q.Key+"="+q.Value)
+			}
+		}
+
+		if len(xs) > 0 {
+			u.Raw += "?" + strings.Join(xs, "&")
+		}
+	}
+
+	return u
+}
+
+func postmanHeaders(hs []api.Header) []postmanHeader {
+	xs := []postmanHeader{}
+
+	for _, h := range hs {
+		xs = append(xs, postmanHeader{Key: h.Key, Value: h.Value})
+	}
+
+	return xs
+}
@@ -0,0 +1,76 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/bukalapak/snowboard/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostman(t *testing.T) {
+	var bf bytes.Buffer
+
+	err := render.Postman(&bf, newMessageAPI())
+	assert.Nil(t, err)
+
+	var c struct {
+		Info struct {
+			Name   string
+			Schema string
+		}
+		Item []struct {
+			Name string
+			Item []struct {
+				Name    string
+				Request struct {
+					Method string
+					Body   struct {
+						Mode string
+						Raw  string
+					}
+					URL struct {
+						Raw      string
+						Host     []string
+						Path     []string
+						Variable []struct{ Key, Value string }
+					}
+				}
+				Response []struct {
+					Code int
+					Body string
+				}
+			}
+		}
+		Variable []struct{ Key, Value string }
+	}
+
+	err = json.Unmarshal(bf.Bytes(), &c)
+	assert.Nil(t, err)
+	assert.Equal(t, "Messages API", c.Info.Name)
+	assert.Equal(t, "https://schema.getpostman.com/json/collection/v2.1.0/collection.json", c.Info.Schema)
+	assert.Equal(t, "baseUrl", c.Variable[0].Key)
+	assert.Equal(t, "https://api.example.com", c.Variable[0].Value)
+
+	assert.Equal(t, "Messages", c.Item[0].Name)
+	assert.Len(t, c.Item[0].Item, 2)
+
+	x := c.Item[0].Item[0]
+	assert.Equal(t, "Retrieve a Message", x.Name)
+	assert.Equal(t, "GET", x.Request.Method)
+	assert.Equal(t, "{{baseUrl}}/messages/:id", x.Request.URL.Raw)
+	assert.Equal(t, []string{"{{baseUrl}}"}, x.Request.URL.Host)
+	assert.Equal(t, []string{"messages", ":id"}, x.Request.URL.Path)
+	assert.Equal(t, "id", x.Request.URL.Variable[0].Key)
+	assert.Equal(t, "1", x.Request.URL.Variable[0].Value)
+	assert.Len(t, x.Response, 2)
+	assert.Equal(t, 200, x.Response[0].Code)
+	assert.Equal(t, `{"id": 1, "body": "Hello"}`, x.Response[0].Body)
+	assert.Equal(t, 404, x.Response[1].Code)
+
+	x = c.Item[0].Item[1]
+	assert.Equal(t, "PUT {{baseUrl}}/messages/:id", x.Name)
+	assert.Equal(t, "raw", x.Request.Body.Mode)
+	assert.Equal(t, `{"body": "Hi"}`, x.Request.Body.Raw)
+}
@@ -1,16 +1,157 @@
 package render
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"html/template"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/bukalapak/snowboard/api"
 	"github.com/gosimple/slug"
 	"github.com/miekg/mmark"
 )
 
+// registry holds functions and partials registered for every template, guarded for templates
+// compiled concurrently, e.g. by servers rendering on request
+var registry = struct {
+	sync.RWMutex
+	funcs    template.FuncMap
+	partials map[string]string
+}{funcs: template.FuncMap{}, partials: map[string]string{}}
+
+// maxCachedTemplates bounds templates compiled by HTML, the cache is emptied when it is full
+const maxCachedTemplates = 16
+
+// cache holds templates compiled by HTML keyed by their source and options, so rendering the same
+// template, e.g. a built-in one, again does not parse it again. Registering functions or partials
+// empties it.
+var cache = struct {
+	sync.Mutex
+	m map[string]*Template
+}{m: map[string]*Template{}}
+
+// Options customize a single template, unlike RegisterFunc and RegisterPartials affecting every
+// template. Their functions take precedence over registered ones, their partials are added to them.
+type Options struct {
+	// Funcs are available to the template, e.g. {"diagram": Diagram}. Cached templates are told
+	// apart by the code of functions, so closures of the same code should be given other names.
+	Funcs template.FuncMap
+	// Partials are templates usable by name, see ReadPartials
+	Partials map[string]string
+}
+
+// RegisterFunc makes fn available to HTML templates as name. Registered functions
+// take precedence over built-in ones, register them before rendering.
+func RegisterFunc(name string, fn interface{}) {
+	registry.Lock()
+	registry.funcs[name] = fn
+	registry.Unlock()
+
+	resetCache()
+}
+
+func resetCache() {
+	cache.Lock()
+	cache.m = map[string]*Template{}
+	cache.Unlock()
+}
+
+// RegisterPartials makes every `*.html` file of dir available to HTML templates, see ReadPartials.
+// It replaces previously registered partials, an empty dir removes them. Register them before
+// rendering.
+func RegisterPartials(dir string) error {
+	ps, err := ReadPartials(dir)
+	if err != nil {
+		return err
+	}
+
+	registry.Lock()
+	changed := !reflect.DeepEqual(ps, registry.partials)
+	if changed {
+		registry.partials = ps
+	}
+	registry.Unlock()
+
+	if changed {
+		resetCache()
+	}
+
+	return nil
+}
+
+// ReadPartials reads every `*.html` file of dir as partial, named after the file without
+// extension, e.g. `{{template "header" .}}` for header.html. Files may define more templates, or
+// override blocks of the main template. An empty dir has no partials.
+func ReadPartials(dir string) (map[string]string, error) {
+	ps := map[string]string{}
+
+	if dir == "" {
+		return ps, nil
+	}
+
+	fs, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fs) == 0 {
+		return nil, fmt.Errorf("%s: no partial templates found", dir)
+	}
+
+	for _, f := range fs {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		ps[strings.TrimSuffix(filepath.Base(f), ".html")] = string(b)
+	}
+
+	return ps, nil
+}
+
+// key identifies o in the template cache, functions by their code
+func (o Options) key() string {
+	var bf bytes.Buffer
+
+	for _, name := range sortedKeys(o.Funcs) {
+		fmt.Fprintf(&bf, "func %s %x\n", name, reflect.ValueOf(o.Funcs[name]).Pointer())
+	}
+
+	names := make([]string, 0, len(o.Partials))
+	for name := range o.Partials {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(&bf, "partial %s %d\n%s", name, len(o.Partials[name]), o.Partials[name])
+	}
+
+	return bf.String()
+}
+
+func sortedKeys(m template.FuncMap) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+
+	sort.Strings(ks)
+
+	return ks
+}
+
 func markdownize(s string) template.HTML {
 	return template.HTML(markdown([]byte(s)))
 }
@@ -56,6 +197,21 @@ func colorize(v interface{}) string {
 	return ""
 }
 
+func jsonPretty(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		var bf bytes.Buffer
+
+		if err := json.Indent(&bf, []byte(s), "", "  "); err == nil {
+			return bf.String(), nil
+		}
+
+		return s, nil
+	}
+
+	b, err := json.MarshalIndent(v, "", "  ")
+	return string(b), err
+}
+
 func alias(s string) string {
 	if strings.Contains(s, "json") {
 		return "json"
@@ -94,24 +250,226 @@ func markdown(input []byte) string {
 	return bf.String()
 }
 
-// HTML renders blueprint.API struct as HTML document
-func HTML(tpl string, w io.Writer, b *api.API) error {
+func anchor(s string) string {
+	return "#" + s
+}
+
+func newHTMLTemplate(tpl string, link func(string) string, opt Options) (*template.Template, error) {
 	funcMap := template.FuncMap{
 		"markdownize":  markdownize,
 		"parameterize": parameterize,
 		"colorize":     colorize,
 		"alias":        alias,
+		"link":         link,
+		"lower":        strings.ToLower,
+		"upper":        strings.ToUpper,
+		"slugify":      parameterize,
+		"jsonPretty":   jsonPretty,
+		"markdownify":  markdownize,
+		"diagram":      func(*api.Resource) template.HTML { return "" },
+	}
+
+	partials := map[string]string{}
+
+	registry.RLock()
+
+	for k, fn := range registry.funcs {
+		funcMap[k] = fn
+	}
+
+	for k, p := range registry.partials {
+		partials[k] = p
+	}
+
+	registry.RUnlock()
+
+	for k, fn := range opt.Funcs {
+		funcMap[k] = fn
+	}
+
+	for k, p := range opt.Partials {
+		partials[k] = p
 	}
 
-	tmpl, err := template.New("html").Funcs(funcMap).Parse(tpl)
+	t, err := template.New("html").Funcs(funcMap).Parse(tpl)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(partials))
+	for name := range partials {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err = t.New(name).Parse(partials[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Template is a parsed HTML template, it can be executed many times and concurrently
+type Template struct {
+	tmpl *template.Template
+}
+
+// Compile parses tpl as HTML template. Functions added by RegisterFunc after Compile are not available to it.
+func Compile(tpl string) (*Template, error) {
+	return Options{}.Compile(tpl)
+}
+
+// Compile parses tpl as HTML template with functions and partials of o
+func (o Options) Compile(tpl string) (*Template, error) {
+	tmpl, err := newHTMLTemplate(tpl, anchor, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute renders blueprint.API struct as HTML document, the template is given its Data
+func (t *Template) Execute(w io.Writer, b *api.API) error {
+	return t.tmpl.Execute(w, NewData(b))
+}
+
+// HTML renders blueprint.API struct as HTML document. Compiled templates are cached, so rendering
+// the same tpl repeatedly parses it once.
+func HTML(tpl string, w io.Writer, b *api.API) error {
+	return Options{}.HTML(tpl, w, b)
+}
+
+// HTML renders blueprint.API struct as HTML document with functions and partials of o. Compiled
+// templates are cached by tpl and o.
+func (o Options) HTML(tpl string, w io.Writer, b *api.API) error {
+	t, err := cachedTemplate(tpl, o)
 	if err != nil {
 		return err
 	}
 
-	err = tmpl.Execute(w, b)
+	return t.Execute(w, b)
+}
+
+func cachedTemplate(tpl string, o Options) (*Template, error) {
+	k := o.key() + "\x00" + tpl
+
+	cache.Lock()
+	t, ok := cache.m[k]
+	cache.Unlock()
+
+	if ok {
+		return t, nil
+	}
+
+	t, err := o.Compile(tpl)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Lock()
+	if len(cache.m) >= maxCachedTemplates {
+		cache.m = map[string]*Template{}
+	}
+
+	cache.m[k] = t
+	cache.Unlock()
+
+	return t, nil
+}
+
+// Precompile parses tpl into the cache of HTML, so its first render does not parse it, e.g. for
+// built-in templates at init
+func Precompile(tpl string) error {
+	return Options{}.Precompile(tpl)
+}
+
+// Precompile parses tpl with functions and partials of o into the cache of HTML
+func (o Options) Precompile(tpl string) error {
+	_, err := cachedTemplate(tpl, o)
+	return err
+}
+
+// HTMLMulti renders blueprint.API struct as HTML documents inside dir, one page for every
+// resource group plus index.html. Template links made by `link` function resolve across pages.
+func HTMLMulti(tpl string, dir string, b *api.API) ([]string, error) {
+	return Options{}.HTMLMulti(tpl, dir, b)
+}
+
+// HTMLMulti renders pages like HTMLMulti function, with functions and partials of o
+func (o Options) HTMLMulti(tpl string, dir string, b *api.API) ([]string, error) {
+	pages := map[string]string{"introduction": "index.html"}
+	names := make([]string, len(b.ResourceGroups))
+	seen := map[string]bool{"index": true}
+
+	for i, g := range b.ResourceGroups {
+		n := parameterize(g.Title)
+		if n == "" || seen[n] {
+			n = fmt.Sprintf("group-%d", i+1)
+		}
+
+		seen[n] = true
+		names[i] = n + ".html"
+
+		if g.Title != "" {
+			pages[parameterize(g.Title)] = names[i]
+		}
+
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				pages[t.Permalink] = names[i]
+			}
+		}
+	}
+
+	link := func(s string) string {
+		return pages[s] + anchor(s)
+	}
+
+	tmpl, err := newHTMLTemplate(tpl, link, o)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := *b
+	idx.ResourceGroups = make([]api.ResourceGroup, len(b.ResourceGroups))
+
+	for i, g := range b.ResourceGroups {
+		idx.ResourceGroups[i] = api.ResourceGroup{Title: g.Title, Description: g.Description}
+	}
+
+	fs := []string{filepath.Join(dir, "index.html")}
+
+	if err := writeHTML(tmpl, fs[0], &idx); err != nil {
+		return nil, err
+	}
+
+	for i, g := range b.ResourceGroups {
+		z := *b
+		z.Description = ""
+		z.ResourceGroups = []api.ResourceGroup{g}
+
+		f := filepath.Join(dir, names[i])
+
+		if err := writeHTML(tmpl, f, &z); err != nil {
+			return nil, err
+		}
+
+		fs = append(fs, f)
+	}
+
+	return fs, nil
+}
+
+func writeHTML(tmpl *template.Template, name string, b *api.API) error {
+	f, err := os.Create(name)
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 
-	return nil
+	return tmpl.Execute(f, NewData(b))
 }
@@ -0,0 +1,268 @@
+package render_test
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/render"
+	"github.com/stretchr/testify/assert"
+)
+
+const navTemplate = `{{range .ResourceGroups}}<h1><a href="{{.Title | parameterize | link}}">{{.Title}}</a></h1>{{range .Resources}}{{range .Transitions}}<a href="{{link .Permalink}}">{{.Method}}</a>{{end}}{{end}}{{end}}`
+
+func TestHTML(t *testing.T) {
+	var bf bytes.Buffer
+
+	err := render.HTML(navTemplate, &bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, `<h1><a href="#messages">Messages</a></h1><a href="#messages-message-retrieve-a-message">GET</a><a href="#">PUT</a>`, bf.String())
+}
+
+func TestCompile(t *testing.T) {
+	tpl, err := render.Compile(navTemplate)
+	assert.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		var bf bytes.Buffer
+
+		err = tpl.Execute(&bf, newMessageAPI())
+		assert.Nil(t, err)
+		assert.Equal(t, `<h1><a href="#messages">Messages</a></h1><a href="#messages-message-retrieve-a-message">GET</a><a href="#">PUT</a>`, bf.String())
+	}
+
+	_, err = render.Compile(`{{.Title`)
+	assert.NotNil(t, err)
+}
+
+func TestHTMLMulti(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	fs, err := render.HTMLMulti(navTemplate, dir, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "index.html"), filepath.Join(dir, "messages.html")}, fs)
+
+	b, err := ioutil.ReadFile(fs[0])
+	assert.Nil(t, err)
+	assert.Equal(t, `<h1><a href="messages.html#messages">Messages</a></h1>`, string(b))
+
+	b, err = ioutil.ReadFile(fs[1])
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `<a href="messages.html#messages-message-retrieve-a-message">GET</a>`)
+}
+
+func TestHTML_funcs(t *testing.T) {
+	render.RegisterFunc("shout", func(s string) string { return s + "!" })
+
+	var bf bytes.Buffer
+
+	tpl := `{{.Title | lower}} {{.Title | upper}} {{.Title | slugify}} {{.Title | shout}} {{jsonPretty "{\"a\":1}"}} {{markdownify "*b*"}}`
+
+	err := render.HTML(tpl, &bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, "messages api MESSAGES API messages-api Messages API! {\n  &#34;a&#34;: 1\n} <p><em>b</em></p>\n", bf.String())
+}
+
+func TestHTML_partials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer render.RegisterPartials("")
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "header.html"), []byte(`<h1>{{.Title}}</h1>`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "footer.html"), []byte(`{{define "copyright"}}(c) {{.Meta "HOST"}}{{end}}`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`{{.Title`), 0644))
+
+	tpl := `{{template "header" .}}{{block "nav" .}}default{{end}}|{{template "copyright" .}}`
+
+	assert.Nil(t, render.RegisterPartials(dir))
+
+	var bf bytes.Buffer
+
+	err = render.HTML(tpl, &bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, `<h1>Messages API</h1>default|(c) https://api.example.com`, bf.String())
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "nav.html"), []byte(`custom`), 0644))
+	assert.Nil(t, render.RegisterPartials(dir))
+
+	bf.Reset()
+	err = render.HTML(tpl, &bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, `<h1>Messages API</h1>custom|(c) https://api.example.com`, bf.String())
+
+	assert.NotNil(t, render.RegisterPartials(filepath.Join(dir, "missing")))
+	assert.Nil(t, render.RegisterPartials(""))
+
+	err = render.HTML(tpl, &bf, newMessageAPI())
+	assert.NotNil(t, err)
+}
+
+func TestHTML_templates(t *testing.T) {
+	for _, name := range []string{"alpha", "beta"} {
+		b, err := ioutil.ReadFile(filepath.Join("..", "templates", name+".html"))
+		assert.Nil(t, err)
+
+		var bf bytes.Buffer
+
+		err = render.HTML(string(b), &bf, newMessageAPI())
+		assert.Nil(t, err, name)
+		assert.Contains(t, bf.String(), `id="messages-message-retrieve-a-message"`, name)
+		assert.Contains(t, bf.String(), `HOST`, name)
+	}
+}
+
+func TestDiagram(t *testing.T) {
+	r := &api.Resource{
+		Transitions: []*api.Transition{
+			{
+				Method: "GET",
+				Transactions: []api.Transaction{
+					{Response: api.Response{StatusCode: 200}},
+					{Response: api.Response{StatusCode: 200}},
+					{Response: api.Response{StatusCode: 404}},
+				},
+			},
+			{Method: "DELETE"},
+		},
+	}
+
+	s := string(render.Diagram(r))
+	assert.True(t, strings.HasPrefix(s, `<svg class="diagram" xmlns="http://www.w3.org/2000/svg" width="191" height="84"`))
+	assert.Equal(t, 4, strings.Count(s, "<rect"))
+	assert.Equal(t, 2, strings.Count(s, "<line"))
+	assert.Contains(t, s, `fill="#fff" text-anchor="middle">404</text>`)
+	assert.Contains(t, s, `fill="#db2828"/>`)
+	assert.Equal(t, "", string(render.Diagram(&api.Resource{})))
+}
+
+func TestDiagram_options(t *testing.T) {
+	bp := newMessageAPI()
+	tpl := `{{range .ResourceGroups}}{{range .Resources}}{{diagram .}}{{end}}{{end}}`
+	opt := render.Options{Funcs: template.FuncMap{"diagram": render.Diagram}}
+
+	var bf bytes.Buffer
+
+	err := opt.HTML(tpl, &bf, bp)
+	assert.Nil(t, err)
+	assert.Contains(t, bf.String(), `<svg class="diagram"`)
+
+	bf.Reset()
+	err = render.HTML(tpl, &bf, bp)
+	assert.Nil(t, err)
+	assert.Equal(t, "", bf.String())
+}
+
+func TestHTML_cache(t *testing.T) {
+	render.RegisterFunc("note", func() string { return "first" })
+
+	var bf bytes.Buffer
+
+	err := render.HTML(`{{note}}`, &bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, "first", bf.String())
+
+	render.RegisterFunc("note", func() string { return "second" })
+
+	bf.Reset()
+	err = render.HTML(`{{note}}`, &bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, "second", bf.String())
+}
+
+func TestOptions_HTML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowboard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "header.html"), []byte(`<h1>{{.Title}}</h1>`), 0644))
+
+	ps, err := render.ReadPartials(dir)
+	assert.Nil(t, err)
+
+	opt := render.Options{
+		Funcs:    template.FuncMap{"diagram": func(*api.Resource) template.HTML { return "<svg/>" }},
+		Partials: ps,
+	}
+
+	tpl := `{{template "header" .}}{{diagram nil}}`
+
+	for i := 0; i < 2; i++ {
+		var bf bytes.Buffer
+
+		err = opt.HTML(tpl, &bf, newMessageAPI())
+		assert.Nil(t, err)
+		assert.Equal(t, `<h1>Messages API</h1><svg/>`, bf.String())
+	}
+
+	var bf bytes.Buffer
+
+	err = render.HTML(`{{diagram nil}}`, &bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.Equal(t, "", bf.String())
+
+	err = render.HTML(tpl, &bf, newMessageAPI())
+	assert.NotNil(t, err)
+
+	_, err = render.ReadPartials(filepath.Join(dir, "missing"))
+	assert.NotNil(t, err)
+}
+
+func TestHTML_concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			render.RegisterFunc("race", func() string { return "" })
+			render.RegisterPartials("")
+		}()
+
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, render.HTML(navTemplate, ioutil.Discard, newMessageAPI()))
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkHTML(b *testing.B) {
+	tpl, err := ioutil.ReadFile(filepath.Join("..", "templates", "alpha.html"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	bp := newMessageAPI()
+
+	b.Run("compile", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			t, err := render.Compile(string(tpl))
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if err = t.Execute(ioutil.Discard, bp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := render.HTML(string(tpl), ioutil.Discard, bp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
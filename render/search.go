@@ -0,0 +1,51 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/bukalapak/snowboard/api"
+)
+
+type searchEntry struct {
+	Title       string `json:"title"`
+	Group       string `json:"group"`
+	Resource    string `json:"resource"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Permalink   string `json:"permalink"`
+}
+
+// SearchIndex renders blueprint.API struct as JSON search index of every transition.
+// The entries follow blueprint order so the output is stable between builds.
+func SearchIndex(w io.Writer, b *api.API) error {
+	xs := []searchEntry{}
+
+	for _, g := range b.ResourceGroups {
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				x := searchEntry{
+					Title:       t.Title,
+					Group:       g.Title,
+					Resource:    r.Title,
+					Method:      t.Method,
+					Path:        r.Href.Path,
+					Description: t.Description,
+					Permalink:   t.Permalink,
+				}
+
+				if t.Href.Path != "" {
+					x.Path = t.Href.Path
+				}
+
+				xs = append(xs, x)
+			}
+		}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(xs)
+}
@@ -0,0 +1,36 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bukalapak/snowboard/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchIndex(t *testing.T) {
+	var bf bytes.Buffer
+
+	err := render.SearchIndex(&bf, newMessageAPI())
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[
+		{
+			"title": "Retrieve a Message",
+			"group": "Messages",
+			"resource": "Message",
+			"method": "GET",
+			"path": "/messages/{id}{?fields}",
+			"description": "",
+			"permalink": "messages-message-retrieve-a-message"
+		},
+		{
+			"title": "",
+			"group": "Messages",
+			"resource": "Message",
+			"method": "PUT",
+			"path": "/messages/{id}{?fields}",
+			"description": "",
+			"permalink": ""
+		}
+	]`, bf.String())
+}
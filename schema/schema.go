@@ -0,0 +1,399 @@
+// Package schema validates JSON documents against JSON Schema (draft 4)
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error describes a single validation failure of a field
+type Error struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+type validator struct {
+	root   map[string]interface{}
+	errors []Error
+}
+
+// Validate validates JSON document against JSON schema
+func Validate(schema, doc []byte) ([]Error, error) {
+	var s map[string]interface{}
+
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %s", err)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+
+	var v interface{}
+
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %s", err)
+	}
+
+	x := &validator{root: s}
+	x.validate(s, v, "")
+
+	return x.errors, nil
+}
+
+func (x *validator) fail(field, format string, args ...interface{}) {
+	if field == "" {
+		field = "(root)"
+	}
+
+	x.errors = append(x.errors, Error{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (x *validator) validate(s map[string]interface{}, v interface{}, field string) {
+	if ref, ok := s["$ref"].(string); ok {
+		if z := x.resolve(ref); z != nil {
+			x.validate(z, v, field)
+		}
+
+		return
+	}
+
+	if t, ok := s["type"]; ok && !matchType(t, v) {
+		x.fail(field, "must be of type %s", typeNames(t))
+		return
+	}
+
+	if xs, ok := s["enum"].([]interface{}); ok && !contains(xs, v) {
+		x.fail(field, "must be one of %s", marshal(xs))
+	}
+
+	for _, z := range schemas(s["allOf"]) {
+		x.validate(z, v, field)
+	}
+
+	if xs := schemas(s["anyOf"]); len(xs) > 0 && x.count(xs, v) == 0 {
+		x.fail(field, "must match at least one schema in anyOf")
+	}
+
+	if xs := schemas(s["oneOf"]); len(xs) > 0 && x.count(xs, v) != 1 {
+		x.fail(field, "must match exactly one schema in oneOf")
+	}
+
+	if z, ok := s["not"].(map[string]interface{}); ok && x.count([]map[string]interface{}{z}, v) == 1 {
+		x.fail(field, "must not match schema in not")
+	}
+
+	switch n := v.(type) {
+	case map[string]interface{}:
+		x.validateObject(s, n, field)
+	case []interface{}:
+		x.validateArray(s, n, field)
+	case string:
+		x.validateString(s, n, field)
+	case json.Number:
+		x.validateNumber(s, n, field)
+	}
+}
+
+func (x *validator) validateObject(s map[string]interface{}, v map[string]interface{}, field string) {
+	props, _ := s["properties"].(map[string]interface{})
+
+	for _, k := range stringSlice(s["required"]) {
+		if _, ok := v[k]; !ok {
+			x.fail(join(field, k), "is required")
+		}
+	}
+
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if z, ok := props[k].(map[string]interface{}); ok {
+			x.validate(z, v[k], join(field, k))
+			continue
+		}
+
+		switch a := s["additionalProperties"].(type) {
+		case bool:
+			if !a {
+				x.fail(join(field, k), "is not allowed")
+			}
+		case map[string]interface{}:
+			x.validate(a, v[k], join(field, k))
+		}
+	}
+
+	if n, ok := number(s["minProperties"]); ok && float64(len(v)) < n {
+		x.fail(field, "must have at least %v properties", n)
+	}
+
+	if n, ok := number(s["maxProperties"]); ok && float64(len(v)) > n {
+		x.fail(field, "must have at most %v properties", n)
+	}
+}
+
+func (x *validator) validateArray(s map[string]interface{}, v []interface{}, field string) {
+	switch items := s["items"].(type) {
+	case map[string]interface{}:
+		for i := range v {
+			x.validate(items, v[i], join(field, strconv.Itoa(i)))
+		}
+	case []interface{}:
+		for i := range v {
+			if i < len(items) {
+				if z, ok := items[i].(map[string]interface{}); ok {
+					x.validate(z, v[i], join(field, strconv.Itoa(i)))
+				}
+			}
+		}
+	}
+
+	if n, ok := number(s["minItems"]); ok && float64(len(v)) < n {
+		x.fail(field, "must have at least %v items", n)
+	}
+
+	if n, ok := number(s["maxItems"]); ok && float64(len(v)) > n {
+		x.fail(field, "must have at most %v items", n)
+	}
+
+	if u, _ := s["uniqueItems"].(bool); u {
+		for i := range v {
+			for j := i + 1; j < len(v); j++ {
+				if reflect.DeepEqual(v[i], v[j]) {
+					x.fail(field, "must have unique items")
+					return
+				}
+			}
+		}
+	}
+}
+
+func (x *validator) validateString(s map[string]interface{}, v string, field string) {
+	n := float64(utf8.RuneCountInString(v))
+
+	if m, ok := number(s["minLength"]); ok && n < m {
+		x.fail(field, "must be at least %v characters", m)
+	}
+
+	if m, ok := number(s["maxLength"]); ok && n > m {
+		x.fail(field, "must be at most %v characters", m)
+	}
+
+	if p, ok := s["pattern"].(string); ok {
+		if re, err := regexp.Compile(p); err == nil && !re.MatchString(v) {
+			x.fail(field, "must match pattern %s", p)
+		}
+	}
+}
+
+func (x *validator) validateNumber(s map[string]interface{}, v json.Number, field string) {
+	f, err := v.Float64()
+	if err != nil {
+		return
+	}
+
+	if m, ok := number(s["minimum"]); ok {
+		if e, _ := s["exclusiveMinimum"].(bool); e && f <= m {
+			x.fail(field, "must be greater than %v", m)
+		} else if f < m {
+			x.fail(field, "must be greater than or equal to %v", m)
+		}
+	}
+
+	if m, ok := number(s["maximum"]); ok {
+		if e, _ := s["exclusiveMaximum"].(bool); e && f >= m {
+			x.fail(field, "must be less than %v", m)
+		} else if f > m {
+			x.fail(field, "must be less than or equal to %v", m)
+		}
+	}
+
+	if m, ok := number(s["multipleOf"]); ok && m != 0 {
+		if q := f / m; q != math.Trunc(q) {
+			x.fail(field, "must be a multiple of %v", m)
+		}
+	}
+}
+
+func (x *validator) count(xs []map[string]interface{}, v interface{}) int {
+	n := 0
+
+	for _, z := range xs {
+		y := &validator{root: x.root}
+		y.validate(z, v, "")
+
+		if len(y.errors) == 0 {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (x *validator) resolve(ref string) map[string]interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+
+	var v interface{} = x.root
+
+	for _, k := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if k == "" {
+			continue
+		}
+
+		k = strings.Replace(strings.Replace(k, "~1", "/", -1), "~0", "~", -1)
+
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		v = m[k]
+	}
+
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func matchType(t interface{}, v interface{}) bool {
+	switch z := t.(type) {
+	case string:
+		return isType(z, v)
+	case []interface{}:
+		for _, s := range z {
+			if n, ok := s.(string); ok && isType(n, v) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func isType(t string, v interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+
+	return true
+}
+
+func typeNames(t interface{}) string {
+	if s, ok := t.(string); ok {
+		return s
+	}
+
+	return marshal(t)
+}
+
+func contains(xs []interface{}, v interface{}) bool {
+	for _, x := range xs {
+		if equal(x, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func equal(a, b interface{}) bool {
+	if n, ok := b.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return false
+		}
+
+		m, ok := a.(float64)
+		return ok && m == f
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func schemas(v interface{}) []map[string]interface{} {
+	var xs []map[string]interface{}
+
+	if zs, ok := v.([]interface{}); ok {
+		for _, z := range zs {
+			if m, ok := z.(map[string]interface{}); ok {
+				xs = append(xs, m)
+			}
+		}
+	}
+
+	return xs
+}
+
+func stringSlice(v interface{}) []string {
+	var xs []string
+
+	if zs, ok := v.([]interface{}); ok {
+		for _, z := range zs {
+			if s, ok := z.(string); ok {
+				xs = append(xs, s)
+			}
+		}
+	}
+
+	return xs
+}
+
+func number(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func join(field, key string) string {
+	if field == "" {
+		return key
+	}
+
+	return field + "." + key
+}
+
+func marshal(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
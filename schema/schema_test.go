@@ -0,0 +1,62 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const userSchema = `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "type": "object",
+  "properties": {
+    "name": {"type": "string", "minLength": 2},
+    "age": {"type": "integer", "minimum": 0},
+    "role": {"type": "string", "enum": ["admin", "member"]},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "address": {"$ref": "#/definitions/Address"}
+  },
+  "required": ["name"],
+  "additionalProperties": false,
+  "definitions": {
+    "Address": {
+      "type": "object",
+      "properties": {"city": {"type": "string"}},
+      "required": ["city"]
+    }
+  }
+}`
+
+func TestValidate(t *testing.T) {
+	errs, err := schema.Validate([]byte(userSchema), []byte(`{"name": "olaf", "age": 30, "role": "admin", "tags": ["a"], "address": {"city": "Bandung"}}`))
+	assert.Nil(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestValidate_errors(t *testing.T) {
+	errs, err := schema.Validate([]byte(userSchema), []byte(`{"age": 1.5, "role": "guest", "tags": ["a", 1], "address": {}, "extra": true}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []schema.Error{
+		{Field: "name", Message: "is required"},
+		{Field: "address.city", Message: "is required"},
+		{Field: "age", Message: "must be of type integer"},
+		{Field: "extra", Message: "is not allowed"},
+		{Field: "role", Message: `must be one of ["admin","member"]`},
+		{Field: "tags.1", Message: "must be of type string"},
+	}, errs)
+}
+
+func TestValidate_root(t *testing.T) {
+	errs, err := schema.Validate([]byte(userSchema), []byte(`[]`))
+	assert.Nil(t, err)
+	assert.Equal(t, []schema.Error{{Field: "(root)", Message: "must be of type object"}}, errs)
+}
+
+func TestValidate_invalid(t *testing.T) {
+	_, err := schema.Validate([]byte(`{`), []byte(`{}`))
+	assert.NotNil(t, err)
+
+	_, err = schema.Validate([]byte(`{}`), []byte(`{"name": `))
+	assert.NotNil(t, err)
+}
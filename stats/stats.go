@@ -0,0 +1,43 @@
+// Package stats summarizes an API blueprint
+package stats
+
+import "github.com/bukalapak/snowboard/api"
+
+// Stats holds counts of blueprint elements
+type Stats struct {
+	ResourceGroups int            `json:"resourceGroups"`
+	Resources      int            `json:"resources"`
+	Transitions    int            `json:"transitions"`
+	Methods        map[string]int `json:"methods"`
+	Documented     int            `json:"documented"`
+	Undocumented   int            `json:"undocumented"`
+	DataStructures int            `json:"dataStructures"`
+}
+
+// Compute counts elements of blueprint b, a transition is documented when it has a description
+func Compute(b *api.API) Stats {
+	s := Stats{
+		ResourceGroups: len(b.ResourceGroups),
+		DataStructures: len(b.DataStructures),
+		Methods:        map[string]int{},
+	}
+
+	for _, g := range b.ResourceGroups {
+		s.Resources += len(g.Resources)
+
+		for _, r := range g.Resources {
+			for _, t := range r.Transitions {
+				s.Transitions++
+				s.Methods[t.Method]++
+
+				if t.Description != "" {
+					s.Documented++
+				} else {
+					s.Undocumented++
+				}
+			}
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,42 @@
+package stats_test
+
+import (
+	"testing"
+
+	"github.com/bukalapak/snowboard/api"
+	"github.com/bukalapak/snowboard/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompute(t *testing.T) {
+	b := &api.API{
+		ResourceGroups: []api.ResourceGroup{
+			{
+				Resources: []*api.Resource{
+					{
+						Transitions: []*api.Transition{
+							{Method: "GET", Description: "List users"},
+							{Method: "POST"},
+						},
+					},
+					{
+						Transitions: []*api.Transition{
+							{Method: "GET"},
+						},
+					},
+				},
+			},
+			{},
+		},
+		DataStructures: []api.DataStructure{{Name: "User"}},
+	}
+
+	s := stats.Compute(b)
+	assert.Equal(t, 2, s.ResourceGroups)
+	assert.Equal(t, 2, s.Resources)
+	assert.Equal(t, 3, s.Transitions)
+	assert.Equal(t, map[string]int{"GET": 2, "POST": 1}, s.Methods)
+	assert.Equal(t, 1, s.Documented)
+	assert.Equal(t, 2, s.Undocumented)
+	assert.Equal(t, 1, s.DataStructures)
+}
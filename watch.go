@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bukalapak/snowboard/loader"
+)
+
+// defaultDebounce is how long watched files must stay unchanged before they are processed, editors
+// may write a file in several steps on save
+const defaultDebounce = 100 * time.Millisecond
+
+// watchedFiles lists local inputs along with their seeds and includes. Listing parses the inputs,
+// so watchers list them again only after a successful regeneration instead of on every poll,
+// picking up seeds and includes added or removed meanwhile.
+func watchedFiles(inputs []string) []string {
+	fs := []string{}
+
+	for _, input := range inputs {
+		if input == loader.Stdin || loader.IsURL(input) {
+			continue
+		}
+
+		fs = append(fs, input)
+
+		// seeds and body files are named relative to the blueprint
+		for _, f := range loader.Seeds(input) {
+			fs = append(fs, filepath.Join(filepath.Dir(input), f))
+		}
+
+		fs = append(fs, loader.Includes(input)...)
+	}
+
+	return fs
+}
+
+// modTimes returns modification times of files, missing ones are left out
+func modTimes(fs []string) map[string]time.Time {
+	m := map[string]time.Time{}
+
+	for _, f := range fs {
+		if fi, err := os.Stat(f); err == nil {
+			m[f] = fi.ModTime()
+		}
+	}
+
+	return m
+}
+
+// settle waits until mod times of files stop changing for d, so rapid changes are handled once.
+// It returns the last mod times.
+func settle(d time.Duration, mt map[string]time.Time, modTimes func() map[string]time.Time) map[string]time.Time {
+	for d > 0 {
+		time.Sleep(d)
+
+		z := modTimes()
+		if sameModTimes(mt, z) {
+			return z
+		}
+
+		mt = z
+	}
+
+	return mt
+}
+
+func sameModTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !w.Equal(v) {
+			return false
+		}
+	}
+
+	return true
+}